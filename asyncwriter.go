@@ -0,0 +1,176 @@
+package tarantool
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("Writer is closed")
+
+type AsyncWriterOptions struct {
+	// Buffered operations are flushed when there are BatchSize of them,
+	// 1000 by default, or every FlushInterval, 1s by default
+	BatchSize     int
+	FlushInterval time.Duration
+	// Parallelism of flushed batches, 16 by default
+	Parallelism int
+	// Writes block when BufferSize operations wait for flush,
+	// 10 batches by default
+	BufferSize int
+	// OnError is called for every failed operation from the flushing
+	// goroutine. Without it errors are sent to Errors channel.
+	OnError func(err error)
+}
+
+// AsyncWriter is a write-behind buffer for writes which don't need
+// acknowledgement. Operations are flushed as a Batch, so their order
+// is not kept.
+type AsyncWriter struct {
+	opts   AsyncWriterOptions
+	ops    chan batchOp
+	flush  chan chan struct{}
+	errors chan error
+	done   chan struct{}
+
+	mutex  sync.RWMutex
+	closed bool
+}
+
+func NewAsyncWriter(opts AsyncWriterOptions) *AsyncWriter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 16
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 10 * opts.BatchSize
+	}
+	writer := &AsyncWriter{
+		opts:   opts,
+		ops:    make(chan batchOp, opts.BufferSize),
+		flush:  make(chan chan struct{}),
+		errors: make(chan error, 100),
+		done:   make(chan struct{}),
+	}
+	go writer.run()
+	return writer
+}
+
+func (writer *AsyncWriter) Insert(space *Space, tuple []TupleField) error {
+	return writer.write(func(returnTuple bool) ([][][]byte, error) {
+		return space.Insert(tuple, returnTuple)
+	})
+}
+
+func (writer *AsyncWriter) Replace(space *Space, tuple []TupleField) error {
+	return writer.write(func(returnTuple bool) ([][][]byte, error) {
+		return space.Replace(tuple, returnTuple)
+	})
+}
+
+// Errors receives failures when OnError is not set. Errors are dropped
+// if channel is not read in time, it is closed by Close.
+func (writer *AsyncWriter) Errors() <-chan error {
+	return writer.errors
+}
+
+// Flush waits until operations buffered before it are written
+func (writer *AsyncWriter) Flush() error {
+	writer.mutex.RLock()
+	defer writer.mutex.RUnlock()
+	if writer.closed {
+		return ErrClosed
+	}
+	flushed := make(chan struct{})
+	writer.flush <- flushed
+	<-flushed
+	return nil
+}
+
+// Close writes all buffered operations and stops writer
+func (writer *AsyncWriter) Close() error {
+	writer.mutex.Lock()
+	if writer.closed {
+		writer.mutex.Unlock()
+		return ErrClosed
+	}
+	writer.closed = true
+	close(writer.ops)
+	writer.mutex.Unlock()
+
+	<-writer.done
+	return nil
+}
+
+func (writer *AsyncWriter) write(op batchOp) error {
+	writer.mutex.RLock()
+	defer writer.mutex.RUnlock()
+	if writer.closed {
+		return ErrClosed
+	}
+	writer.ops <- op
+	return nil
+}
+
+func (writer *AsyncWriter) run() {
+	defer close(writer.done)
+	defer close(writer.errors)
+	ticker := time.NewTicker(writer.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := writer.newBatch()
+	for {
+		select {
+		case op, ok := <-writer.ops:
+			if !ok {
+				writer.execute(batch)
+				return
+			}
+			batch.add(op)
+			if batch.Len() < writer.opts.BatchSize {
+				continue
+			}
+		case flushed := <-writer.flush:
+			// take what is buffered already
+			for len(writer.ops) > 0 {
+				batch.add(<-writer.ops)
+			}
+			writer.execute(batch)
+			batch = writer.newBatch()
+			close(flushed)
+			continue
+		case <-ticker.C:
+		}
+		writer.execute(batch)
+		batch = writer.newBatch()
+	}
+}
+
+func (writer *AsyncWriter) newBatch() *Batch {
+	return &Batch{Parallelism: writer.opts.Parallelism}
+}
+
+func (writer *AsyncWriter) execute(batch *Batch) {
+	if batch.Len() == 0 {
+		return
+	}
+	results, _ := batch.Execute()
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		if writer.opts.OnError != nil {
+			writer.opts.OnError(result.Err)
+			continue
+		}
+		select {
+		case writer.errors <- result.Err:
+		default:
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tarantool
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAsyncWriter(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(13)
+	defer space.Truncate()
+
+	writer := NewAsyncWriter(AsyncWriterOptions{BatchSize: 10, FlushInterval: time.Hour})
+	for i := 0; i < 25; i++ {
+		err := writer.Insert(space, []TupleField{String(strconv.Itoa(i)), String("event")})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+	}
+	// there is no such space
+	writer.Insert(conn.Space(100), []TupleField{String("0"), String("lost")})
+
+	err := writer.Flush()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if n, _ := space.Len(); n != 25 {
+		t.Errorf("25 tuples should be written after flush, not %d", n)
+	}
+
+	writer.Replace(space, []TupleField{String("last"), String("event")})
+	err = writer.Close()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if n, _ := space.Len(); n != 26 {
+		t.Errorf("Buffer should be written on close, %d tuples found", n)
+	}
+
+	errors := 0
+	for range writer.Errors() {
+		errors++
+	}
+	if errors != 1 {
+		t.Errorf("Failed insert should be reported once, not %d times", errors)
+	}
+	if writer.Insert(space, nil) != ErrClosed {
+		t.Errorf("Closed writer should not accept writes")
+	}
+}
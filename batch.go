@@ -0,0 +1,120 @@
+package tarantool
+
+import (
+	"fmt"
+	"sync"
+)
+
+type BatchResult struct {
+	Tuples [][][]byte
+	Err    error
+}
+
+// Batch collects write operations on any spaces of a connection and runs
+// them concurrently, so requests are pipelined by iproto.
+// Operations are independent, order of their execution is not defined.
+type Batch struct {
+	// Parallelism is number of requests in flight, 16 by default
+	Parallelism int
+	// ReturnTuples asks for tuples in results
+	ReturnTuples bool
+
+	ops []batchOp
+}
+
+type batchOp func(returnTuple bool) ([][][]byte, error)
+
+func NewBatch() *Batch {
+	return &Batch{Parallelism: 16}
+}
+
+func (batch *Batch) Insert(space *Space, tuple []TupleField) *Batch {
+	return batch.add(func(returnTuple bool) ([][][]byte, error) {
+		return space.Insert(tuple, returnTuple)
+	})
+}
+
+func (batch *Batch) Add(space *Space, tuple []TupleField) *Batch {
+	return batch.add(func(returnTuple bool) ([][][]byte, error) {
+		return space.Add(tuple, returnTuple)
+	})
+}
+
+func (batch *Batch) Replace(space *Space, tuple []TupleField) *Batch {
+	return batch.add(func(returnTuple bool) ([][][]byte, error) {
+		return space.Replace(tuple, returnTuple)
+	})
+}
+
+func (batch *Batch) Update(space *Space, key []TupleField, ops ...UpdOp) *Batch {
+	return batch.add(func(returnTuple bool) ([][][]byte, error) {
+		return space.Update(key, returnTuple, ops...)
+	})
+}
+
+func (batch *Batch) Delete(space *Space, key []TupleField) *Batch {
+	return batch.add(func(returnTuple bool) ([][][]byte, error) {
+		return space.Delete(key, returnTuple)
+	})
+}
+
+func (batch *Batch) Len() int {
+	return len(batch.ops)
+}
+
+func (batch *Batch) add(op batchOp) *Batch {
+	batch.ops = append(batch.ops, op)
+	return batch
+}
+
+// Execute runs all operations, results are in the order operations
+// were added. Error tells how many of them failed, see Retry.
+func (batch *Batch) Execute() (results []BatchResult, err error) {
+	results = make([]BatchResult, len(batch.ops))
+	parallelism := batch.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism && w < len(batch.ops); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i].Tuples, results[i].Err = batch.ops[i](batch.ReturnTuples)
+			}
+		}()
+	}
+	for i := range batch.ops {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	failed, first := 0, error(nil)
+	for _, result := range results {
+		if result.Err != nil {
+			if first == nil {
+				first = result.Err
+			}
+			failed++
+		}
+	}
+	if failed > 0 {
+		err = fmt.Errorf("%d of %d batch operations failed, first error: %s", failed, len(results), first.Error())
+	}
+	return
+}
+
+// Retry returns a new batch of operations failed in results
+func (batch *Batch) Retry(results []BatchResult) *Batch {
+	retry := &Batch{Parallelism: batch.Parallelism, ReturnTuples: batch.ReturnTuples}
+	for i, result := range results {
+		if result.Err != nil && i < len(batch.ops) {
+			retry.add(batch.ops[i])
+		}
+	}
+	return retry
+}
@@ -0,0 +1,45 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(11)
+	defer space.Truncate()
+
+	batch := NewBatch()
+	for _, name := range []string{"a", "b", "c"} {
+		batch.Insert(space, []TupleField{String(name), String("owner")})
+	}
+	batch.Add(space, []TupleField{String("a"), String("duplicate")})
+	batch.Update(space, []TupleField{String("missing")}, UpdOp{1, OpEq, String("x")})
+
+	results, err := batch.Execute()
+	if err == nil {
+		t.Errorf("Failed Add should be reported")
+	}
+	if len(results) != 5 {
+		t.Fatalf("5 results expected, not %d", len(results))
+	}
+	for i := 0; i < 3; i++ {
+		if results[i].Err != nil {
+			t.Errorf("Error: %s", results[i].Err.Error())
+		}
+	}
+	if ErrorCode(results[3].Err) != CodeTupleFound {
+		t.Errorf("Add of existing tuple should fail, got %v", results[3].Err)
+	}
+
+	retry := batch.Retry(results)
+	if retry.Len() != 1 {
+		t.Errorf("Only Add should be retried, not %d operations", retry.Len())
+	}
+
+	space.Delete([]TupleField{String("a")}, false)
+	_, err = retry.Execute()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+}
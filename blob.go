@@ -0,0 +1,276 @@
+package tarantool
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrChecksum = errors.New("Blob checksum mismatch")
+
+const (
+	defaultChunkSize = 64 * 1024
+	defaultReadAhead = 4
+)
+
+// BlobInfo is kept as tuple {blob_id STR, size NUM64, chunk_size NUM,
+// chunks NUM, sha256 STR, metadata STR} with metadata encoded as json
+type BlobInfo struct {
+	Id        string
+	Size      int64
+	ChunkSize int
+	Chunks    int
+	Checksum  []byte
+	Metadata  map[string]string
+}
+
+// BlobStore keeps values bigger than a tuple can hold as chunks
+// {blob_id STR, chunk_no NUM, data STR} in a space with TREE index
+// on (blob_id, chunk_no). Info is written after all chunks,
+// so blob without info is incomplete.
+type BlobStore struct {
+	chunks *Space
+	info   *Space
+
+	// ChunkSize is used for new blobs, 64KB by default
+	ChunkSize int
+	// ReadAhead is number of chunks read by one select, 4 by default
+	ReadAhead int32
+}
+
+func (conn *Connection) BlobStore(chunkSpaceNo, infoSpaceNo int32) *BlobStore {
+	return &BlobStore{conn.Space(chunkSpaceNo), conn.Space(infoSpaceNo), defaultChunkSize, defaultReadAhead}
+}
+
+// Put reads blob from reader chunk by chunk, existing blob with the same id
+// is replaced
+func (store *BlobStore) Put(id string, reader io.Reader, metadata map[string]string) (info *BlobInfo, err error) {
+	err = store.Delete(id)
+	if err != nil && err != ErrNotFound {
+		return
+	}
+	meta, err := json.Marshal(metadata)
+	if err != nil {
+		return
+	}
+
+	chunkSize := store.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	info = &BlobInfo{Id: id, ChunkSize: chunkSize, Metadata: metadata}
+	hash := sha256.New()
+	buf := make([]byte, chunkSize)
+	for {
+		var n int
+		n, err = io.ReadFull(reader, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return
+		}
+		hash.Write(buf[:n])
+		_, err = store.chunks.Insert([]TupleField{String(id), Int32(info.Chunks), String(buf[:n])}, false)
+		if err != nil {
+			return
+		}
+		info.Chunks++
+		info.Size += int64(n)
+		if n < len(buf) {
+			break
+		}
+	}
+	info.Checksum = hash.Sum(nil)
+
+	_, err = store.info.Insert([]TupleField{
+		String(id),
+		Int64(info.Size),
+		Int32(info.ChunkSize),
+		Int32(info.Chunks),
+		String(info.Checksum),
+		String(meta),
+	}, false)
+	return
+}
+
+// Stat returns ErrNotFound for missing and incomplete blobs
+func (store *BlobStore) Stat(id string) (info *BlobInfo, err error) {
+	tuples, err := store.info.Select(0, 0, 1, []TupleField{String(id)})
+	if err != nil {
+		return
+	}
+	if len(tuples) == 0 {
+		err = ErrNotFound
+		return
+	}
+	tuple := tuples[0]
+	if len(tuple) < 6 {
+		err = fmt.Errorf("Blob info should have 6 fields, not %d", len(tuple))
+		return
+	}
+
+	var (
+		size              Int64
+		chunkSize, chunks Int32
+	)
+	for _, unpack := range []error{size.Unpack(tuple[1]), chunkSize.Unpack(tuple[2]), chunks.Unpack(tuple[3])} {
+		if unpack != nil {
+			err = unpack
+			return
+		}
+	}
+	info = &BlobInfo{string(tuple[0]), int64(size), int(chunkSize), int(chunks), tuple[4], nil}
+	err = json.Unmarshal(tuple[5], &info.Metadata)
+	return
+}
+
+// Open returns reader of a complete blob
+func (store *BlobStore) Open(id string) (reader *BlobReader, err error) {
+	info, err := store.Stat(id)
+	if err != nil {
+		return
+	}
+	if info.ChunkSize <= 0 && info.Size > 0 {
+		err = fmt.Errorf("Blob %s has wrong chunk size %d", id, info.ChunkSize)
+		return
+	}
+	reader = &BlobReader{store: store, info: info}
+	return
+}
+
+// Delete removes info first, so blob becomes incomplete,
+// then all of its chunks
+func (store *BlobStore) Delete(id string) (err error) {
+	deleted, err := store.info.Delete([]TupleField{String(id)}, true)
+	if err != nil {
+		return
+	}
+
+	for {
+		var tuples [][][]byte
+		tuples, err = store.chunks.Select(0, 0, 100, []TupleField{String(id)})
+		if err != nil || len(tuples) == 0 {
+			break
+		}
+		for _, tuple := range tuples {
+			_, err = store.chunks.Delete(rawTuple(tuple[:2]), false)
+			if err != nil {
+				return
+			}
+		}
+	}
+	if err == nil && len(deleted) == 0 {
+		err = ErrNotFound
+	}
+	return
+}
+
+// Verify reads the whole blob and compares its checksum
+func (store *BlobStore) Verify(id string) (err error) {
+	reader, err := store.Open(id)
+	if err != nil {
+		return
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, reader)
+	if err != nil {
+		return
+	}
+	if !bytes.Equal(hash.Sum(nil), reader.info.Checksum) {
+		err = ErrChecksum
+	}
+	return
+}
+
+// BlobReader reads chunks on demand, ReadAhead chunks at a time
+type BlobReader struct {
+	store  *BlobStore
+	info   *BlobInfo
+	offset int64
+	cached map[int][]byte
+}
+
+func (reader *BlobReader) Info() *BlobInfo {
+	return reader.info
+}
+
+func (reader *BlobReader) Read(p []byte) (n int, err error) {
+	for n < len(p) && reader.offset < reader.info.Size {
+		chunkNo := int(reader.offset / int64(reader.info.ChunkSize))
+		var chunk []byte
+		chunk, err = reader.chunk(chunkNo)
+		if err != nil {
+			return
+		}
+		start := int(reader.offset % int64(reader.info.ChunkSize))
+		if start >= len(chunk) {
+			err = fmt.Errorf("Blob %s chunk %d is truncated", reader.info.Id, chunkNo)
+			return
+		}
+		copied := copy(p[n:], chunk[start:])
+		n += copied
+		reader.offset += int64(copied)
+	}
+	if n == 0 && len(p) > 0 {
+		err = io.EOF
+	}
+	return
+}
+
+func (reader *BlobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += reader.offset
+	case io.SeekEnd:
+		offset += reader.info.Size
+	default:
+		return reader.offset, fmt.Errorf("Unknown whence %d", whence)
+	}
+	if offset < 0 {
+		return reader.offset, fmt.Errorf("Negative offset %d", offset)
+	}
+	reader.offset = offset
+	return offset, nil
+}
+
+// chunk selects range of chunks starting from (blob_id, chunkNo) key
+// unless it is cached already
+func (reader *BlobReader) chunk(chunkNo int) (chunk []byte, err error) {
+	chunk, ok := reader.cached[chunkNo]
+	if ok {
+		return
+	}
+
+	readAhead := reader.store.ReadAhead
+	if readAhead <= 0 {
+		readAhead = defaultReadAhead
+	}
+	tuples, err := reader.store.chunks.SelectRange(0, readAhead, String(reader.info.Id), Int32(chunkNo))
+	if err != nil {
+		return
+	}
+	reader.cached = map[int][]byte{}
+	for _, tuple := range tuples {
+		// range goes on to the next blob
+		if len(tuple) < 3 || string(tuple[0]) != reader.info.Id {
+			break
+		}
+		var no Int32
+		err = no.Unpack(tuple[1])
+		if err != nil {
+			return
+		}
+		reader.cached[int(no)] = tuple[2]
+	}
+
+	chunk, ok = reader.cached[chunkNo]
+	if !ok {
+		err = fmt.Errorf("Blob %s chunk %d is missing", reader.info.Id, chunkNo)
+	}
+	return
+}
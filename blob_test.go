@@ -0,0 +1,151 @@
+package tarantool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestBlobStore(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	store := conn.BlobStore(6, 7)
+	store.ChunkSize = 1000
+	defer conn.Space(6).Truncate()
+	defer conn.Space(7).Truncate()
+
+	data := bytes.Repeat([]byte("0123456789"), 1050)
+	info, err := store.Put("blob", bytes.NewReader(data), map[string]string{"type": "text/plain"})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if info.Size != 10500 || info.Chunks != 11 {
+		t.Errorf("Blob of 10500 bytes in 11 chunks expected, got %d in %d", info.Size, info.Chunks)
+	}
+
+	// overwritten blob is deleted and written again
+	_, err = store.Put("blob", bytes.NewReader(data[:10]), nil)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	_, err = store.Put("blob", bytes.NewReader(data), map[string]string{"type": "text/plain"})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+
+	info, err = store.Stat("blob")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if info.Metadata["type"] != "text/plain" {
+		t.Errorf("Metadata should be kept, got %v", info.Metadata)
+	}
+
+	reader, err := store.Open("blob")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	reader.Seek(-1505, io.SeekEnd)
+	tail, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if !bytes.Equal(tail, data[9000-5:]) {
+		t.Errorf("Wrong tail of %d bytes read", len(tail))
+	}
+
+	err = store.Verify("blob")
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+
+	err = store.Delete("blob")
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	_, err = store.Stat("blob")
+	if err != ErrNotFound {
+		t.Errorf("Deleted blob should not be found")
+	}
+	if n, _ := conn.Space(6).Len(); n != 0 {
+		t.Errorf("All chunks should be deleted, %d left", n)
+	}
+}
+
+func TestBlobStoreDefaults(t *testing.T) {
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		return nil, nil
+	})
+	store := conn.BlobStore(6, 7)
+	store.ChunkSize, store.ReadAhead = 0, 0
+
+	info, err := store.Put("blob", bytes.NewReader(make([]byte, 100*1024)), nil)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if info.ChunkSize != 64*1024 || info.Chunks != 2 {
+		t.Errorf("Default chunk size should be used, got %d bytes in %d chunks", info.ChunkSize, info.Chunks)
+	}
+}
+
+func TestBlobStorePutFlags(t *testing.T) {
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		if req.Op == InsertOp {
+			flags := int32(binary.LittleEndian.Uint32(req.Body[4:8]))
+			if flags&BoxReplace != 0 {
+				t.Errorf("Blob tuples don't exist before Put, they can't be replaced")
+			}
+		}
+		return nil, nil
+	})
+	store := conn.BlobStore(6, 7)
+	store.ChunkSize = 4
+
+	_, err := store.Put("blob", bytes.NewReader([]byte("0123456789")), nil)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+}
+
+func TestBlobReaderRanges(t *testing.T) {
+	chunks := [][]byte{[]byte("012"), []byte("345"), []byte("678"), []byte("9")}
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		if req.Op == SelectOp {
+			size, chunkSize, count := make([]byte, 8), make([]byte, 4), make([]byte, 4)
+			binary.LittleEndian.PutUint64(size, 10)
+			binary.LittleEndian.PutUint32(chunkSize, 3)
+			binary.LittleEndian.PutUint32(count, 4)
+			return [][][]byte{{[]byte("blob"), size, chunkSize, count, nil, []byte("null")}}, nil
+		}
+		// box.select_range(space, index, limit, blob_id, chunk_no)
+		args, _ := req.Keys()
+		if req.ProcName() != "box.select_range" || len(args[0]) != 5 {
+			t.Fatalf("Chunks should be selected by range from key, got %s %q", req.ProcName(), args)
+		}
+		from := int(binary.LittleEndian.Uint32(args[0][4]))
+		tuples := [][][]byte{}
+		for no := from; no < len(chunks) && no < from+2; no++ {
+			tuples = append(tuples, [][]byte{[]byte("blob"), {byte(no), 0, 0, 0}, chunks[no]})
+		}
+		return append(tuples, [][]byte{[]byte("next"), {0, 0, 0, 0}, []byte("xxx")}), nil
+	})
+	store := conn.BlobStore(6, 7)
+	store.ReadAhead = 2
+
+	reader, err := store.Open("blob")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	reader.Seek(4, io.SeekStart)
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if string(data) != "456789" {
+		t.Errorf("456789 should be read, not %q", data)
+	}
+}
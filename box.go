@@ -0,0 +1,81 @@
+package tarantool
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// Typed wrappers for box.* lua procedures.
+// Numeric arguments are passed as strings, as lua expects them,
+// key fields are packed as is.
+
+// SelectRange returns up to limit tuples starting from key in TREE index order,
+// empty key means the first tuple
+func (space *Space) SelectRange(indexNo, limit int32, key ...TupleField) (tuples [][][]byte, err error) {
+	tuples, err = space.Call("box.select_range", true, space.procArgs(key, indexNo, limit)...)
+	return
+}
+
+// SelectReverseRange is SelectRange going backward,
+// empty key means the last tuple
+func (space *Space) SelectReverseRange(indexNo, limit int32, key ...TupleField) (tuples [][][]byte, err error) {
+	tuples, err = space.Call("box.select_reverse_range", true, space.procArgs(key, indexNo, limit)...)
+	return
+}
+
+// SelectLimit returns tuples matching key skipping offset of them
+func (space *Space) SelectLimit(indexNo, offset, limit int32, key ...TupleField) (tuples [][][]byte, err error) {
+	tuples, err = space.Call("box.select_limit", true, space.procArgs(key, indexNo, offset, limit)...)
+	return
+}
+
+// DoString executes lua code, args are available in it as `...`
+func (space *Space) DoString(code string, args ...TupleField) (tuples [][][]byte, err error) {
+	tuples, err = space.Call("box.dostring", true, append([]TupleField{String(code)}, args...)...)
+	return
+}
+
+// Len returns number of tuples in space
+func (space *Space) Len() (count int64, err error) {
+	tuples, err := space.DoString(fmt.Sprintf("return box.space[%d]:len()", space.spaceNo))
+	if err != nil {
+		return
+	}
+	if len(tuples) != 1 || len(tuples[0]) != 1 {
+		err = fmt.Errorf("Unexpected box.space[%d]:len() result %v", space.spaceNo, tuples)
+		return
+	}
+	count, err = UnpackNumber(tuples[0][0])
+	return
+}
+
+// Truncate deletes all tuples from space
+func (space *Space) Truncate() (err error) {
+	_, err = space.DoString(fmt.Sprintf("box.space[%d]:truncate()", space.spaceNo))
+	return
+}
+
+// UnpackNumber decodes number returned from lua,
+// it comes as 4 bytes when it fits and as 8 bytes otherwise
+func UnpackNumber(field []byte) (n int64, err error) {
+	switch len(field) {
+	case 4:
+		n = int64(binary.LittleEndian.Uint32(field))
+	case 8:
+		n = int64(binary.LittleEndian.Uint64(field))
+	default:
+		err = fmt.Errorf("Number should be 4 or 8 bytes long, not %d", len(field))
+	}
+	return
+}
+
+// procArgs prepends space number and numeric args to key
+func (space *Space) procArgs(key []TupleField, nums ...int32) (args []TupleField) {
+	args = []TupleField{String(strconv.Itoa(int(space.spaceNo)))}
+	for _, num := range nums {
+		args = append(args, String(strconv.Itoa(int(num))))
+	}
+	args = append(args, key...)
+	return
+}
@@ -0,0 +1,100 @@
+package tarantool
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnpackNumber(t *testing.T) {
+	n, err := UnpackNumber([]byte{2, 1, 0, 0})
+	if err != nil || n != 258 {
+		t.Errorf("4 bytes should be unpacked into 258 not %d, %v", n, err)
+	}
+	n, err = UnpackNumber([]byte{0, 0, 0, 0, 1, 0, 0, 0})
+	if err != nil || n != 1<<32 {
+		t.Errorf("8 bytes should be unpacked into %d not %d, %v", int64(1<<32), n, err)
+	}
+	if _, err = UnpackNumber([]byte{1}); err == nil {
+		t.Errorf("Error expected for 1 byte number")
+	}
+}
+
+func TestBoxProcedures(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(0)
+
+	ids := []int32{1001, 1002, 1003}
+	for _, id := range ids {
+		space.Delete([]TupleField{Int32(id)}, false)
+	}
+	before, err := space.Len()
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	for _, id := range ids {
+		space.Insert([]TupleField{Int32(id), String("BoxProcedures")}, false)
+	}
+	defer func() {
+		for _, id := range ids {
+			space.Delete([]TupleField{Int32(id)}, false)
+		}
+	}()
+
+	count, err := space.Len()
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if count != before+3 {
+		t.Errorf("Space should have %d tuples not %d", before+3, count)
+	}
+
+	res, err := space.SelectRange(0, 2, Int32(1002))
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if firstIds(res) != "1002 1003" {
+		t.Errorf("1002 and 1003 should be selected from 1002, not %s", firstIds(res))
+	}
+
+	res, err = space.SelectReverseRange(0, 2, Int32(1002))
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if firstIds(res) != "1002 1001" {
+		t.Errorf("1002 and 1001 should be selected backward from 1002, not %s", firstIds(res))
+	}
+
+	res, err = space.SelectLimit(1, 1, 10, String("BoxProcedures"))
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if len(res) != 2 {
+		t.Errorf("2 tuples should be selected with offset 1 not %d", len(res))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	// Check test space
+	space := conn.Space(9)
+	space.Insert([]TupleField{Int32(1), String("Peter")}, false)
+
+	err := space.Truncate()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	count, _ := space.Len()
+	if count != 0 {
+		t.Errorf("Space should be empty after truncate, got %d tuples", count)
+	}
+}
+
+func firstIds(tuples [][][]byte) string {
+	ids := []string{}
+	for _, tuple := range tuples {
+		n, _ := UnpackNumber(tuple[0])
+		ids = append(ids, strconv.FormatInt(n, 10))
+	}
+	return strings.Join(ids, " ")
+}
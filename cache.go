@@ -0,0 +1,172 @@
+package tarantool
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keeps []byte values with expiration in a space with tuples
+// {key STR, value STR, expires NUM64}, where expires is unix time
+// in milliseconds or 0 for values which never expire. Primary index
+// is on key, expiry TREE index on expires is used by sweeper.
+//
+// Expired values are not returned, sweeper deletes them in background.
+type Cache struct {
+	space       *Space
+	expiryIndex int32
+}
+
+func (space *Space) Cache(expiryIndex int32) *Cache {
+	return &Cache{space, expiryIndex}
+}
+
+// Get returns ok false for missing and expired values
+func (cache *Cache) Get(key string) (value []byte, ok bool, err error) {
+	tuples, err := cache.space.Select(0, 0, 1, []TupleField{String(key)})
+	if err != nil || len(tuples) == 0 {
+		return
+	}
+	value, ok = cache.value(tuples[0], time.Now())
+	return
+}
+
+// Set stores value, zero ttl means it never expires
+func (cache *Cache) Set(key string, value []byte, ttl time.Duration) (err error) {
+	_, err = cache.space.Insert(cache.tuple(key, value, ttl), false)
+	return
+}
+
+func (cache *Cache) Delete(key string) (err error) {
+	_, err = cache.space.Delete([]TupleField{String(key)}, false)
+	return
+}
+
+// GetMulti selects all keys at once, missing and expired keys are absent in values
+func (cache *Cache) GetMulti(keys []string) (values map[string][]byte, err error) {
+	values = map[string][]byte{}
+	if len(keys) == 0 {
+		return
+	}
+	selectKeys := make([][]TupleField, len(keys))
+	for i, key := range keys {
+		selectKeys[i] = []TupleField{String(key)}
+	}
+
+	tuples, err := cache.space.Select(0, 0, int32(len(keys)), selectKeys...)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, tuple := range tuples {
+		if value, ok := cache.value(tuple, now); ok {
+			values[string(tuple[0])] = value
+		}
+	}
+	return
+}
+
+// SetMulti stores values with parallel requests, the first error is returned
+func (cache *Cache) SetMulti(values map[string][]byte, ttl time.Duration) (err error) {
+	batch := NewBatch()
+	for key, value := range values {
+		batch.Insert(cache.space, cache.tuple(key, value, ttl))
+	}
+	err = firstError(batch.Execute())
+	return
+}
+
+// Sweep deletes expired values by batches walking expiry index
+// from its start, values without expiration are skipped.
+// Batch is 100 by default.
+func (cache *Cache) Sweep(batch int32) (deleted int, err error) {
+	if batch <= 0 {
+		batch = 100
+	}
+	for {
+		now := unixMillis(time.Now())
+		var tuples [][][]byte
+		tuples, err = cache.space.SelectRange(cache.expiryIndex, batch, Int64(1))
+		if err != nil {
+			return
+		}
+
+		deletes := NewBatch()
+		for _, tuple := range tuples {
+			if len(tuple) < 3 || expiresAt(tuple) > now {
+				break
+			}
+			deletes.Delete(cache.space, []TupleField{String(tuple[0])})
+		}
+		err = firstError(deletes.Execute())
+		if err != nil {
+			return
+		}
+		deleted += deletes.Len()
+
+		if deletes.Len() < len(tuples) || int32(len(tuples)) < batch {
+			return
+		}
+	}
+}
+
+func firstError(results []BatchResult, err error) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return err
+}
+
+// StartSweeper runs Sweep every interval until stop is called,
+// sweep errors are passed to onError unless it is nil
+func (cache *Cache) StartSweeper(interval time.Duration, batch int32, onError func(error)) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			_, err := cache.Sweep(batch)
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (cache *Cache) tuple(key string, value []byte, ttl time.Duration) []TupleField {
+	expires := int64(0)
+	if ttl > 0 {
+		expires = unixMillis(time.Now().Add(ttl))
+	}
+	return []TupleField{String(key), String(value), Int64(expires)}
+}
+
+func (cache *Cache) value(tuple [][]byte, now time.Time) (value []byte, ok bool) {
+	if len(tuple) < 3 {
+		return
+	}
+	if expires := expiresAt(tuple); expires != 0 && expires <= unixMillis(now) {
+		return
+	}
+	return tuple[1], true
+}
+
+func expiresAt(tuple [][]byte) int64 {
+	var expires Int64
+	expires.Unpack(tuple[2])
+	return int64(expires)
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,72 @@
+package tarantool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	cache := conn.Space(3).Cache(1)
+	defer conn.Space(3).Truncate()
+
+	err := cache.Set("forever", []byte("value"), 0)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	err = cache.SetMulti(map[string][]byte{"a": []byte("1"), "b": []byte("2")}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+
+	values, err := cache.GetMulti([]string{"a", "b", "c", "forever"})
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if len(values) != 3 || string(values["b"]) != "2" {
+		t.Errorf("3 values expected, got %q", values)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok, err := cache.Get("a")
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if ok {
+		t.Errorf("Expired value should not be returned")
+	}
+
+	deleted, err := cache.Sweep(1)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if deleted != 2 {
+		t.Errorf("2 expired values should be swept, not %d", deleted)
+	}
+
+	value, ok, _ := cache.Get("forever")
+	if !ok || string(value) != "value" {
+		t.Errorf("Value without ttl should stay, got %q", value)
+	}
+	cache.Delete("forever")
+	if _, ok, _ = cache.Get("forever"); ok {
+		t.Errorf("Deleted value should not be returned")
+	}
+}
+
+func TestSweepDefaultBatch(t *testing.T) {
+	selects := 0
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		selects++
+		return nil, nil
+	})
+
+	deleted, err := conn.Space(3).Cache(1).Sweep(0)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if deleted != 0 || selects != 1 {
+		t.Errorf("Empty cache should be swept with one select, not %d", selects)
+	}
+}
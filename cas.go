@@ -0,0 +1,236 @@
+package tarantool
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var (
+	ErrConflict = errors.New("Compare and set conflict")
+	ErrNotFound = errors.New("Tuple not found")
+)
+
+var casProc = Builtins.Register("go_tarantool_cas", 1, `
+function go_tarantool_cas(space, field, expected, nkey, ops, ...)
+	space, field, nkey = tonumber(space), tonumber(field), tonumber(nkey)
+	local args = {...}
+	local key = {}
+	for i = 1, nkey do
+		key[i] = args[i]
+	end
+	local tuple = box.select(space, 0, unpack(key))
+	if tuple == nil then
+		return 'missing'
+	end
+	if tuple[field] ~= expected then
+		return 'conflict', tuple
+	end
+	local opargs = {}
+	for i = nkey + 1, #args, 2 do
+		table.insert(opargs, tonumber(args[i]))
+		table.insert(opargs, args[i + 1])
+	end
+	if nkey == 1 then
+		key = key[1]
+	end
+	return 'ok', box.update(space, key, ops, unpack(opargs))
+end`).Procedure("go_tarantool_cas")
+
+// Update op codes as box.update format expects them
+var casOps = map[int8]string{
+	OpEq:      "=",
+	OpAdd:     "+",
+	OpAnd:     "&",
+	OpXor:     "^",
+	OpOr:      "|",
+	OpDelete:  "#",
+	OpPrepend: "!",
+}
+
+// CompareAndSet applies ops to tuple with primary key only if its field
+// equals expected one. ErrConflict is returned with the current tuple
+// when it doesn't, ErrNotFound when there is no such tuple.
+func (space *Space) CompareAndSet(key []TupleField, fieldNo int32, expected TupleField, ops ...UpdOp) (tuples [][][]byte, err error) {
+	format := ""
+	opArgs := []TupleField{}
+	for _, op := range ops {
+		opFormat, ok := casOps[op.OpCode]
+		if !ok {
+			err = fmt.Errorf("Update op %d is not supported by compare and set", op.OpCode)
+			return
+		}
+		format += opFormat + "p"
+		opArgs = append(opArgs, String(strconv.Itoa(int(op.FieldNo))), op.Field)
+	}
+
+	args := []TupleField{
+		String(strconv.Itoa(int(space.spaceNo))),
+		String(strconv.Itoa(int(fieldNo))),
+		expected,
+		String(strconv.Itoa(len(key))),
+		String(format),
+	}
+	args = append(args, key...)
+	args = append(args, opArgs...)
+
+	res, err := casProc.Call(space, args...)
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0]) != 1 {
+		err = fmt.Errorf("Unexpected compare and set result %v", res)
+		return
+	}
+	tuples = res[1:]
+	switch string(res[0][0]) {
+	case "missing":
+		err = ErrNotFound
+	case "conflict":
+		err = ErrConflict
+	}
+	return
+}
+
+// record maps struct fields to tuple fields in order.
+// Fields tagged `tarantool:"key"` form primary key,
+// one tagged `tarantool:"version"` is a version counter.
+type record struct {
+	fields  []reflect.Value
+	key     []int
+	version int
+}
+
+func mapRecord(rec interface{}) (mapped *record, err error) {
+	value := reflect.ValueOf(rec)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		err = fmt.Errorf("Record should be a pointer to struct, not %T", rec)
+		return
+	}
+	value = value.Elem()
+
+	mapped = &record{version: -1}
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		if structField.PkgPath != "" {
+			continue
+		}
+		field := value.Field(i)
+		if _, ok := field.Interface().(TupleField); !ok {
+			err = fmt.Errorf("Record field %s should implement TupleField", structField.Name)
+			return
+		}
+
+		switch structField.Tag.Get("tarantool") {
+		case "key":
+			mapped.key = append(mapped.key, len(mapped.fields))
+		case "version":
+			switch field.Interface().(type) {
+			case Int32, Int64:
+			default:
+				err = fmt.Errorf("Record version %s should be Int32 or Int64", structField.Name)
+				return
+			}
+			mapped.version = len(mapped.fields)
+		}
+		mapped.fields = append(mapped.fields, field)
+	}
+
+	if len(mapped.key) == 0 || mapped.version < 0 {
+		err = fmt.Errorf("Record %T should have key and version fields", rec)
+	}
+	return
+}
+
+func (mapped *record) tupleField(i int) TupleField {
+	return mapped.fields[i].Interface().(TupleField)
+}
+
+func (mapped *record) keyFields() (key []TupleField) {
+	for _, i := range mapped.key {
+		key = append(key, mapped.tupleField(i))
+	}
+	return
+}
+
+// LoadRecord selects record by its key fields and unpacks all fields into it,
+// ErrNotFound is returned when there is no such tuple
+func (space *Space) LoadRecord(rec interface{}) (err error) {
+	mapped, err := mapRecord(rec)
+	if err != nil {
+		return
+	}
+	tuples, err := space.Select(0, 0, 1, mapped.keyFields())
+	if err != nil {
+		return
+	}
+	if len(tuples) == 0 {
+		return ErrNotFound
+	}
+
+	for i, field := range mapped.fields {
+		if i >= len(tuples[0]) {
+			break
+		}
+		unpacker, ok := field.Addr().Interface().(interface {
+			Unpack([]byte) error
+		})
+		if !ok {
+			return fmt.Errorf("Record field %d of %T can't be unpacked", i, rec)
+		}
+		err = unpacker.Unpack(tuples[0][i])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// SaveRecord writes all non key fields of the record if stored version
+// equals record one and increments version, ErrConflict means record
+// was changed by someone else since it was loaded
+//
+//	type Account struct {
+//		Id      tarantool.Int32 `tarantool:"key"`
+//		Balance tarantool.Int32
+//		Version tarantool.Int32 `tarantool:"version"`
+//	}
+func (space *Space) SaveRecord(rec interface{}) (err error) {
+	mapped, err := mapRecord(rec)
+	if err != nil {
+		return
+	}
+
+	ops := []UpdOp{}
+	for i := range mapped.fields {
+		switch {
+		case i == mapped.version:
+			var one TupleField = Int32(1)
+			if _, ok := mapped.tupleField(i).(Int64); ok {
+				one = Int64(1)
+			}
+			ops = append(ops, UpdOp{int32(i), OpAdd, one})
+		case !mapped.isKey(i):
+			ops = append(ops, UpdOp{int32(i), OpEq, mapped.tupleField(i)})
+		}
+	}
+
+	_, err = space.CompareAndSet(mapped.keyFields(), int32(mapped.version), mapped.tupleField(mapped.version), ops...)
+	if err != nil {
+		return
+	}
+
+	version := mapped.fields[mapped.version]
+	version.SetInt(version.Int() + 1)
+	return
+}
+
+func (mapped *record) isKey(i int) bool {
+	for _, k := range mapped.key {
+		if k == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+type Account struct {
+	Id      Int32 `tarantool:"key"`
+	Owner   String
+	Version Int32 `tarantool:"version"`
+}
+
+func TestMapRecord(t *testing.T) {
+	account := &Account{1, "Mary", 0}
+	mapped, err := mapRecord(account)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if len(mapped.fields) != 3 || len(mapped.key) != 1 || mapped.version != 2 {
+		t.Errorf("Record should have 3 fields, key 0 and version 2, not %+v", mapped)
+	}
+
+	_, err = mapRecord(Account{})
+	if err == nil {
+		t.Errorf("Error expected for struct passed by value")
+	}
+	_, err = mapRecord(&struct{ Name String }{})
+	if err == nil {
+		t.Errorf("Error expected for struct without key and version")
+	}
+}
+
+func TestCompareAndSet(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(0)
+	defer space.Delete([]TupleField{Int32(1)}, false)
+
+	_, err := space.Insert([]TupleField{Int32(1), String("Mary"), Int32(0)}, false)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+
+	key := []TupleField{Int32(1)}
+	res, err := space.CompareAndSet(key, 2, Int32(0), UpdOp{1, OpEq, String("Linda")}, UpdOp{2, OpAdd, Int32(1)})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if len(res) != 1 || string(res[0][1]) != "Linda" {
+		t.Errorf("Owner should be changed to Linda, got %q", res)
+	}
+
+	_, err = space.CompareAndSet(key, 2, Int32(0), UpdOp{1, OpEq, String("Peter")})
+	if err != ErrConflict {
+		t.Errorf("ErrConflict expected for outdated version, not %v", err)
+	}
+	_, err = space.CompareAndSet([]TupleField{Int32(2)}, 2, Int32(0), UpdOp{1, OpEq, String("Peter")})
+	if err != ErrNotFound {
+		t.Errorf("ErrNotFound expected for missing tuple, not %v", err)
+	}
+
+	account := &Account{Id: 1}
+	err = space.LoadRecord(account)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	stale := *account
+
+	account.Owner = "John"
+	err = space.SaveRecord(account)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if account.Version != 2 {
+		t.Errorf("Version should be incremented to 2, not %d", account.Version)
+	}
+
+	stale.Owner = "Peter"
+	err = space.SaveRecord(&stale)
+	if err != ErrConflict {
+		t.Errorf("ErrConflict expected for stale record, not %v", err)
+	}
+}
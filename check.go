@@ -0,0 +1,376 @@
+package tarantool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type DiffKind int
+
+const (
+	DiffMissing DiffKind = iota // tuple exists on the left side only
+	DiffExtra                   // tuple exists on the right side only
+	DiffChanged                 // tuples with the same key differ
+)
+
+type Diff struct {
+	Kind  DiffKind
+	Key   [][]byte
+	Left  [][]byte
+	Right [][]byte
+}
+
+type CheckOptions struct {
+	IndexNo int32 // unique TREE index to walk, primary one by default
+	// Key fields of the index are needed to merge both sides in index order,
+	// {{0, NUM}} by default
+	Key []KeyField
+	// Tuples per box.select_range request, 1000 by default
+	BatchSize int32
+	// Tuples hashed together, only mismatching ranges are compared
+	// tuple by tuple, 100 by default
+	RangeSize int
+	// Mismatching ranges are split in halves until they have LeafSize
+	// tuples on the left side, then their tuples are fetched, 16 by default
+	LeafSize int
+}
+
+type CheckReport struct {
+	Left       int64 // tuples on the left side
+	Right      int64 // tuples on the right side
+	Ranges     int
+	Mismatched int // ranges with different hashes
+	Diffs      []Diff
+}
+
+var checkProc = Builtins.Register("go_tarantool_check", 1, `
+local function go_tarantool_check_cmp(tuple, fieldnos, types, key)
+	for i, fieldno in ipairs(fieldnos) do
+		local a, b = tuple[fieldno], key[i]
+		if types[i] == 'NUM' then
+			a, b = box.unpack('i', a), box.unpack('i', b)
+		elseif types[i] == 'NUM64' then
+			a, b = box.unpack('l', a), box.unpack('l', b)
+		end
+		if a < b then
+			return -1
+		elseif a > b then
+			return 1
+		end
+	end
+	return 0
+end
+
+-- fnv-1a and djb2 together, bit ops keep numbers in 32 bits
+local function go_tarantool_check_hash(h1, h2, s)
+	for i = 1, #s do
+		local c = string.byte(s, i)
+		h1 = bit.bxor(h1, c)
+		h1 = bit.tobit(bit.lshift(h1, 24) + h1 * 403)
+		h2 = bit.tobit(h2 * 33 + c)
+	end
+	return h1, h2
+end
+
+function go_tarantool_check(space, index, batch, limit, fieldnos, types, nstart, nupper, ...)
+	space, index, batch, limit = tonumber(space), tonumber(index), tonumber(batch), tonumber(limit)
+	nstart, nupper = tonumber(nstart), tonumber(nupper)
+	local args = {...}
+	local nos, kinds, start, upper = {}, {}, {}, {}
+	for no in string.gmatch(fieldnos, '%d+') do
+		table.insert(nos, tonumber(no))
+	end
+	for kind in string.gmatch(types, '%w+') do
+		table.insert(kinds, kind)
+	end
+	for i = 1, nstart do
+		start[i] = args[i]
+	end
+	for i = 1, nupper do
+		upper[i] = args[nstart + i]
+	end
+
+	local count, h1, h2, last = 0, bit.tobit(2166136261), 5381, nil
+	while true do
+		local tuples = {box.select_range(space, index, batch, unpack(start))}
+		for _, tuple in ipairs(tuples) do
+			if nstart == 0 or go_tarantool_check_cmp(tuple, nos, kinds, start) > 0 then
+				if nupper > 0 and go_tarantool_check_cmp(tuple, nos, kinds, upper) > 0 then
+					tuples = {}
+					break
+				end
+				h1, h2 = go_tarantool_check_hash(h1, h2, #tuple .. ':')
+				for i = 0, #tuple - 1 do
+					h1, h2 = go_tarantool_check_hash(h1, h2, #tuple[i] .. ':')
+					h1, h2 = go_tarantool_check_hash(h1, h2, tuple[i])
+				end
+				count, last = count + 1, tuple
+				if count == limit then
+					tuples = {}
+					break
+				end
+			end
+		end
+		if #tuples < batch then
+			break
+		end
+		start, nstart = {}, #nos
+		for i, fieldno in ipairs(nos) do
+			start[i] = tuples[#tuples][fieldno]
+		end
+	end
+
+	local result = {tostring(count), tostring(h1), tostring(h2)}
+	if last ~= nil then
+		for _, fieldno in ipairs(nos) do
+			table.insert(result, last[fieldno])
+		end
+	end
+	return result
+end`).Procedure("go_tarantool_check")
+
+// rangeDigest is a hash of tuples in a range, Last is key of the last one
+type rangeDigest struct {
+	Count int
+	Hash  string
+	Last  [][]byte
+}
+
+type checker struct {
+	left, right *Space
+	opts        CheckOptions
+	keyFields   []int32
+	report      *CheckReport
+}
+
+// Check walks the same space on two connections, e.g. master and replica,
+// in index order and reports missing, extra and changed tuples.
+// Ranges of RangeSize left tuples are hashed on server by a lua procedure,
+// the right range is cut by the last left key. Mismatching ranges are
+// split and hashed again, tuples are fetched only for small ranges
+// which still differ.
+func Check(left, right *Space, opts CheckOptions) (report *CheckReport, err error) {
+	report = new(CheckReport)
+	if opts.Key == nil {
+		opts.Key = []KeyField{{0, NumType}}
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	if opts.RangeSize <= 0 {
+		opts.RangeSize = 100
+	}
+	if opts.LeafSize <= 0 {
+		opts.LeafSize = 16
+	}
+	c := &checker{left, right, opts, keyFieldNos(opts.Key), report}
+
+	var start [][]byte
+	for {
+		var leftDigest, rightDigest rangeDigest
+		leftDigest, err = c.digest(left, start, nil, opts.RangeSize)
+		if err != nil {
+			return
+		}
+
+		// when left side is over the rest of right side goes by RangeSize
+		upper := leftDigest.Last
+		if leftDigest.Count > 0 {
+			rightDigest, err = c.digest(right, start, upper, 0)
+		} else {
+			rightDigest, err = c.digest(right, start, nil, opts.RangeSize)
+			upper = rightDigest.Last
+		}
+		if err != nil {
+			return
+		}
+		if leftDigest.Count == 0 && rightDigest.Count == 0 {
+			return
+		}
+
+		report.Left += int64(leftDigest.Count)
+		report.Right += int64(rightDigest.Count)
+		report.Ranges++
+		if leftDigest.Hash != rightDigest.Hash {
+			report.Mismatched++
+			err = c.diff(start, upper, leftDigest.Count)
+			if err != nil {
+				return
+			}
+		}
+		start = upper
+	}
+}
+
+// diff finds differences in range (start, upper] with leftCount left tuples
+func (c *checker) diff(start, upper [][]byte, leftCount int) (err error) {
+	if leftCount <= c.opts.LeafSize {
+		var leftTuples, rightTuples [][][]byte
+		leftTuples, err = c.tuples(c.left, start, upper)
+		if err != nil {
+			return
+		}
+		rightTuples, err = c.tuples(c.right, start, upper)
+		if err != nil {
+			return
+		}
+		c.report.Diffs = append(c.report.Diffs, diffRanges(leftTuples, rightTuples, c.keyFields, c.opts.Key)...)
+		return
+	}
+
+	half, err := c.digest(c.left, start, upper, leftCount/2)
+	if err != nil || half.Count == 0 {
+		// left side has changed meanwhile
+		if err == nil {
+			err = fmt.Errorf("Range of %d tuples has no tuples on the second check", leftCount)
+		}
+		return
+	}
+	for _, bounds := range [][2][][]byte{{start, half.Last}, {half.Last, upper}} {
+		var leftDigest, rightDigest rangeDigest
+		leftDigest, err = c.digest(c.left, bounds[0], bounds[1], 0)
+		if err != nil {
+			return
+		}
+		rightDigest, err = c.digest(c.right, bounds[0], bounds[1], 0)
+		if err != nil {
+			return
+		}
+		if leftDigest.Hash != rightDigest.Hash {
+			err = c.diff(bounds[0], bounds[1], leftDigest.Count)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// digest hashes up to limit tuples in range (start, upper] on server,
+// nil bounds and zero limit mean no bound
+func (c *checker) digest(space *Space, start, upper [][]byte, limit int) (digest rangeDigest, err error) {
+	fieldNos, types := make([]string, len(c.opts.Key)), make([]string, len(c.opts.Key))
+	for i, keyField := range c.opts.Key {
+		fieldNos[i], types[i] = strconv.Itoa(int(keyField.FieldNo)), keyField.Type
+	}
+	args := []TupleField{
+		String(strconv.Itoa(int(space.spaceNo))),
+		String(strconv.Itoa(int(c.opts.IndexNo))),
+		String(strconv.Itoa(int(c.opts.BatchSize))),
+		String(strconv.Itoa(limit)),
+		String(strings.Join(fieldNos, ",")),
+		String(strings.Join(types, ",")),
+		String(strconv.Itoa(len(start))),
+		String(strconv.Itoa(len(upper))),
+	}
+	args = append(append(args, rawTuple(start)...), rawTuple(upper)...)
+
+	res, err := checkProc.Call(space, args...)
+	if err != nil {
+		return
+	}
+	if len(res) != 1 || len(res[0]) < 3 {
+		err = fmt.Errorf("Unexpected check result %v", res)
+		return
+	}
+	digest.Count, err = strconv.Atoi(string(res[0][0]))
+	if err != nil {
+		return
+	}
+	digest.Hash = string(res[0][0]) + ":" + string(res[0][1]) + ":" + string(res[0][2])
+	if digest.Count > 0 {
+		digest.Last = res[0][3:]
+	}
+	return
+}
+
+// tuples fetches range (start, upper]
+func (c *checker) tuples(space *Space, start, upper [][]byte) (tuples [][][]byte, err error) {
+	iter := space.Iterator(IteratorOptions{
+		IndexNo:   c.opts.IndexNo,
+		KeyFields: c.keyFields,
+		Start:     rawTuple(start),
+		BatchSize: c.opts.BatchSize,
+	})
+	for iter.Next() {
+		key := tupleKey(iter.Tuple(), c.keyFields)
+		if start != nil && compareKeys(key, start, c.opts.Key) <= 0 {
+			continue
+		}
+		if upper != nil && compareKeys(key, upper, c.opts.Key) > 0 {
+			break
+		}
+		tuples = append(tuples, iter.Tuple())
+	}
+	err = iter.Err()
+	return
+}
+
+func diffRanges(left, right [][][]byte, keyFields []int32, key []KeyField) (diffs []Diff) {
+	for len(left) > 0 || len(right) > 0 {
+		var cmp int
+		switch {
+		case len(left) == 0:
+			cmp = 1
+		case len(right) == 0:
+			cmp = -1
+		default:
+			cmp = compareKeys(tupleKey(left[0], keyFields), tupleKey(right[0], keyFields), key)
+		}
+
+		switch {
+		case cmp < 0:
+			diffs = append(diffs, Diff{DiffMissing, tupleKey(left[0], keyFields), left[0], nil})
+			left = left[1:]
+		case cmp > 0:
+			diffs = append(diffs, Diff{DiffExtra, tupleKey(right[0], keyFields), nil, right[0]})
+			right = right[1:]
+		default:
+			if !sameKey(left[0], right[0]) {
+				diffs = append(diffs, Diff{DiffChanged, tupleKey(left[0], keyFields), left[0], right[0]})
+			}
+			left, right = left[1:], right[1:]
+		}
+	}
+	return
+}
+
+// compareKeys orders keys as tarantool TREE index does
+func compareKeys(a, b [][]byte, key []KeyField) int {
+	for i, keyField := range key {
+		var cmp int
+		switch {
+		case keyField.Type == NumType && len(a[i]) == 4 && len(b[i]) == 4:
+			cmp = compareUint(uint64(binary.LittleEndian.Uint32(a[i])), uint64(binary.LittleEndian.Uint32(b[i])))
+		case keyField.Type == Num64Type && len(a[i]) == 8 && len(b[i]) == 8:
+			cmp = compareUint(binary.LittleEndian.Uint64(a[i]), binary.LittleEndian.Uint64(b[i]))
+		default:
+			cmp = bytes.Compare(a[i], b[i])
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func keyFieldNos(key []KeyField) (keyFields []int32) {
+	keyFields = make([]int32, len(key))
+	for i, keyField := range key {
+		keyFields[i] = keyField.FieldNo
+	}
+	return
+}
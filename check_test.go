@@ -0,0 +1,89 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestDiffRanges(t *testing.T) {
+	key := []KeyField{{0, NumType}}
+	left := [][][]byte{
+		{{1, 0, 0, 0}, []byte("Peter")},
+		{{2, 0, 0, 0}, []byte("Mary")},
+		{{0, 1, 0, 0}, []byte("Linda")},
+	}
+	right := [][][]byte{
+		{{2, 0, 0, 0}, []byte("Marry")},
+		{{3, 0, 0, 0}, []byte("John")},
+		{{0, 1, 0, 0}, []byte("Linda")},
+	}
+
+	diffs := diffRanges(left, right, keyFieldNos(key), key)
+	kinds := []DiffKind{DiffMissing, DiffChanged, DiffExtra}
+	if len(diffs) != len(kinds) {
+		t.Fatalf("%d diffs expected not %d: %v", len(kinds), len(diffs), diffs)
+	}
+	for i, diff := range diffs {
+		if diff.Kind != kinds[i] {
+			t.Errorf("Diff %d should be of kind %d not %d", i, kinds[i], diff.Kind)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(0)
+
+	for i := 1; i <= 5; i++ {
+		space.Insert([]TupleField{Int32(i), String("Peter")}, false)
+	}
+	defer func() {
+		for i := 1; i <= 5; i++ {
+			space.Delete([]TupleField{Int32(i)}, false)
+		}
+	}()
+
+	report, err := Check(space, space, CheckOptions{BatchSize: 2, RangeSize: 3})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if report.Left != 5 || report.Ranges != 2 || len(report.Diffs) != 0 {
+		t.Errorf("Space should match itself in 2 ranges, got %+v", report)
+	}
+}
+
+func TestCheckDiffs(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	left, right := conn.Space(8), conn.Space(9)
+	defer left.Truncate()
+	defer right.Truncate()
+
+	for i := 1; i <= 100; i++ {
+		left.Insert([]TupleField{Int32(i), String("Peter")}, false)
+		switch i {
+		case 7:
+			// missing on the right side
+		case 42:
+			right.Insert([]TupleField{Int32(i), String("Paul")}, false)
+		default:
+			right.Insert([]TupleField{Int32(i), String("Peter")}, false)
+		}
+	}
+	right.Insert([]TupleField{Int32(1000), String("Mary")}, false)
+
+	report, err := Check(left, right, CheckOptions{BatchSize: 10, RangeSize: 30, LeafSize: 4})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if report.Left != 100 || report.Right != 100 || report.Ranges != 5 || report.Mismatched != 3 {
+		t.Errorf("Wrong report %+v", report)
+	}
+	kinds := []DiffKind{DiffMissing, DiffChanged, DiffExtra}
+	if len(report.Diffs) != len(kinds) {
+		t.Fatalf("%d diffs expected not %d: %v", len(kinds), len(report.Diffs), report.Diffs)
+	}
+	for i, diff := range report.Diffs {
+		if diff.Kind != kinds[i] {
+			t.Errorf("Diff %d should be of kind %d not %d", i, kinds[i], diff.Kind)
+		}
+	}
+}
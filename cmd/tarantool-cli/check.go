@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+var diffNames = map[tarantool.DiffKind]string{
+	tarantool.DiffMissing: "missing",
+	tarantool.DiffExtra:   "extra",
+	tarantool.DiffChanged: "changed",
+}
+
+func runCheck(conn *tarantool.Connection, schema *tarantool.Schema, args []string) (err error) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	spaceNo := flags.Int("space", 0, "space to check")
+	indexNo := flags.Int("index", 0, "unique TREE index to walk")
+	keyList := flags.String("key", "", "comma separated key fields of index as fieldno[:TYPE], taken from -config by default")
+	with := flags.String("with", "", "address to compare with, e.g. replica")
+	withSpaceNo := flags.Int("with-space", -1, "space to compare with, the same as checked one by default")
+	batch := flags.Int("batch", 1000, "tuples per request")
+	rangeSize := flags.Int("range", 100, "tuples hashed together")
+	leafSize := flags.Int("leaf", 16, "mismatching ranges are split down to this size before tuples are fetched")
+	flags.Parse(args)
+
+	spaceSchema := schemaSpace(schema, int32(*spaceNo))
+	key, err := indexKey(spaceSchema, int32(*indexNo), *keyList)
+	if err != nil {
+		return
+	}
+	if *withSpaceNo < 0 {
+		*withSpaceNo = *spaceNo
+	}
+	if *with == "" && *withSpaceNo == *spaceNo {
+		return fmt.Errorf("Space can't be checked against itself, use -with or -with-space")
+	}
+
+	withConn := conn
+	if *with != "" {
+		withConn, err = tarantool.Connect(*with)
+		if err != nil {
+			return
+		}
+	}
+
+	report, err := tarantool.Check(conn.Space(int32(*spaceNo)), withConn.Space(int32(*withSpaceNo)), tarantool.CheckOptions{
+		IndexNo:   int32(*indexNo),
+		Key:       key,
+		BatchSize: int32(*batch),
+		RangeSize: *rangeSize,
+		LeafSize:  *leafSize,
+	})
+	if err != nil {
+		return
+	}
+
+	for _, diff := range report.Diffs {
+		fmt.Printf("%-8s %s\n", diffNames[diff.Kind], formatTuple(diff.Key, nil))
+		if diff.Left != nil {
+			fmt.Printf("  < %s\n", formatTuple(diff.Left, spaceSchema))
+		}
+		if diff.Right != nil {
+			fmt.Printf("  > %s\n", formatTuple(diff.Right, spaceSchema))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "compared %d and %d tuples in %d ranges, %d ranges mismatched\n",
+		report.Left, report.Right, report.Ranges, report.Mismatched)
+	if len(report.Diffs) > 0 {
+		err = fmt.Errorf("%d differences found", len(report.Diffs))
+	}
+	return
+}
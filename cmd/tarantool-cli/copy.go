@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+func runCopy(conn *tarantool.Connection, schema *tarantool.Schema, args []string) (err error) {
+	flags := flag.NewFlagSet("copy", flag.ExitOnError)
+	spaceNo := flags.Int("space", 0, "space to copy")
+	indexNo := flags.Int("index", 0, "TREE index to walk")
+	keyList := flags.String("key", "", "comma separated key fields of index as fieldno[:TYPE], taken from -config by default")
+	to := flags.String("to", "", "destination address, source one by default")
+	toSpaceNo := flags.Int("to-space", -1, "destination space, the same as source by default")
+	batch := flags.Int("batch", 1000, "tuples per request")
+	rate := flags.Int("rate", 0, "tuples per second, unlimited by default")
+	verify := flags.Bool("verify", false, "check that space sizes match after copy")
+	flags.Parse(args)
+
+	key, err := indexKey(schemaSpace(schema, int32(*spaceNo)), int32(*indexNo), *keyList)
+	if err != nil {
+		return
+	}
+	if *toSpaceNo < 0 {
+		*toSpaceNo = *spaceNo
+	}
+	if *to == "" && *toSpaceNo == *spaceNo {
+		return fmt.Errorf("Space can't be copied into itself, use -to or -to-space")
+	}
+
+	dstConn := conn
+	if *to != "" {
+		dstConn, err = tarantool.Connect(*to)
+		if err != nil {
+			return
+		}
+	}
+
+	stats, err := tarantool.Copy(conn.Space(int32(*spaceNo)), dstConn.Space(int32(*toSpaceNo)), tarantool.CopyOptions{
+		IndexNo:   int32(*indexNo),
+		KeyFields: keyFieldNos(key),
+		BatchSize: int32(*batch),
+		Rate:      *rate,
+		Verify:    *verify,
+	})
+	fmt.Fprintf(os.Stderr, "read %d tuples, written %d\n", stats.Read, stats.Written)
+	if err == nil && *verify {
+		fmt.Fprintf(os.Stderr, "verified: %d tuples in source and destination\n", stats.DestCount)
+	}
+	return
+}
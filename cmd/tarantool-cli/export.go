@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+// exporter writes tuples as JSON Lines or CSV
+type exporter struct {
+	hints []string
+	space *tarantool.SpaceSchema
+	json  *json.Encoder
+	csv   *csv.Writer
+}
+
+func runExport(conn *tarantool.Connection, schema *tarantool.Schema, args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	spaceNo := flags.Int("space", 0, "space to export")
+	indexNo := flags.Int("index", 0, "TREE index to page through")
+	keyList := flags.String("key", "", "comma separated key fields of index as fieldno[:TYPE], taken from -config by default")
+	fieldList := flags.String("fields", "", "comma separated field types (i8, i32, i64, s, x), guessed by default")
+	format := flags.String("format", "jsonl", "output format, jsonl or csv")
+	batch := flags.Int("batch", 1000, "tuples per request")
+	output := flags.String("o", "", "output file, stdout by default")
+	flags.Parse(args)
+
+	fieldHints, err := parseHints(*fieldList)
+	if err != nil {
+		return
+	}
+	spaceSchema := schemaSpace(schema, int32(*spaceNo))
+	key, err := indexKey(spaceSchema, int32(*indexNo), *keyList)
+	if err != nil {
+		return
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			return
+		}
+		defer out.Close()
+	}
+	exp := &exporter{hints: fieldHints, space: spaceSchema}
+	switch *format {
+	case "jsonl":
+		exp.json = json.NewEncoder(out)
+	case "csv":
+		exp.csv = csv.NewWriter(out)
+	default:
+		return fmt.Errorf("Unknown format %q", *format)
+	}
+
+	iter := conn.Space(int32(*spaceNo)).Iterator(tarantool.IteratorOptions{
+		IndexNo:   int32(*indexNo),
+		KeyFields: keyFieldNos(key),
+		BatchSize: int32(*batch),
+	})
+	count := 0
+	for iter.Next() {
+		err = exp.write(iter.Tuple())
+		if err != nil {
+			return
+		}
+		count++
+	}
+	err = iter.Err()
+	if err != nil {
+		return
+	}
+
+	if exp.csv != nil {
+		exp.csv.Flush()
+		err = exp.csv.Error()
+	}
+	fmt.Fprintf(os.Stderr, "exported %d tuples\n", count)
+	return
+}
+
+func (exp *exporter) write(tuple [][]byte) error {
+	values := make([]interface{}, len(tuple))
+	for i, field := range tuple {
+		values[i] = exp.value(field, i)
+	}
+
+	if exp.json != nil {
+		return exp.json.Encode(values)
+	}
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = fmt.Sprint(value)
+	}
+	return exp.csv.Write(record)
+}
+
+// value decodes field so that import parses it back into the same bytes.
+// Guessed numbers and strings looking like hinted values carry inline hints.
+func (exp *exporter) value(field []byte, i int) interface{} {
+	hint := ""
+	if i < len(exp.hints) {
+		hint = exp.hints[i]
+	}
+	if hint == "" {
+		hint = fieldHint(exp.space, int32(i))
+	}
+	if hint == "x" {
+		return hex.EncodeToString(field)
+	}
+
+	switch val := decodeField(field, hint).(type) {
+	case []byte:
+		return "x:" + hex.EncodeToString(val)
+	case string:
+		if hint == "" && looksHinted(val) {
+			return "s:" + val
+		}
+		return val
+	case uint64:
+		// import can't tell width of a guessed number
+		if hint == "" {
+			return fmt.Sprintf("%s:%d", widthHints[len(field)], val)
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// Hints of guessed numbers by field size
+var widthHints = map[int]string{1: "i8", 4: "i32", 8: "i64"}
+
+func looksHinted(value string) bool {
+	i := strings.Index(value, ":")
+	return i > 0 && hints[value[:i]]
+}
+
+// indexKey returns key fields from the list of `fieldno[:TYPE]` items
+// or from schema, NUM is the default type
+func indexKey(space *tarantool.SpaceSchema, indexNo int32, list string) (key []tarantool.KeyField, err error) {
+	if list != "" {
+		for _, item := range strings.Split(list, ",") {
+			keyField := tarantool.KeyField{Type: tarantool.NumType}
+			parts := strings.SplitN(strings.TrimSpace(item), ":", 2)
+			if len(parts) == 2 {
+				keyField.Type = strings.ToUpper(parts[1])
+			}
+			keyField.FieldNo, err = parseNo(parts[0])
+			if err != nil {
+				return
+			}
+			key = append(key, keyField)
+		}
+		return
+	}
+	if space != nil && space.Index(indexNo) != nil {
+		key = space.Index(indexNo).KeyFields
+		return
+	}
+	if indexNo != 0 {
+		err = fmt.Errorf("Key fields of index %d are unknown, use -key or -config", indexNo)
+		return
+	}
+	key = []tarantool.KeyField{{FieldNo: 0, Type: tarantool.NumType}}
+	return
+}
+
+func keyFieldNos(key []tarantool.KeyField) (keyFields []int32) {
+	for _, keyField := range key {
+		keyFields = append(keyFields, keyField.FieldNo)
+	}
+	return
+}
+
+func schemaSpace(schema *tarantool.Schema, spaceNo int32) *tarantool.SpaceSchema {
+	if schema == nil {
+		return nil
+	}
+	return schema.Space(spaceNo)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+// Field type hints, e.g. i32:1 or s:Peter
+var hints = map[string]bool{
+	"i8":  true,
+	"i32": true,
+	"i64": true,
+	"s":   true,
+	"x":   true, // hex encoded raw bytes
+}
+
+// Schema types are mapped to hints
+var schemaHints = map[string]string{
+	tarantool.NumType:   "i32",
+	tarantool.Num64Type: "i64",
+	tarantool.StrType:   "s",
+}
+
+// parseField packs `hint:value` argument into a tuple field.
+// Without hint the default one is used, strings are default for empty hint.
+func parseField(arg string, hint string) (field tarantool.TupleField, err error) {
+	value := arg
+	if i := strings.Index(arg, ":"); i > 0 && hints[arg[:i]] {
+		hint, value = arg[:i], arg[i+1:]
+	}
+	field, err = packValue(value, hint)
+	if err != nil {
+		err = fmt.Errorf("Can't parse field %q: %s", arg, err.Error())
+	}
+	return
+}
+
+// packValue packs value of a known type, hints inside value are not parsed
+func packValue(value string, hint string) (field tarantool.TupleField, err error) {
+	var i int64
+	switch hint {
+	case "i8":
+		i, err = parseInt(value, 8)
+		field = tarantool.Int8(i)
+	case "i32":
+		i, err = parseInt(value, 32)
+		field = tarantool.Int32(i)
+	case "i64":
+		i, err = parseInt(value, 64)
+		field = tarantool.Int64(i)
+	case "x":
+		var raw []byte
+		raw, err = hex.DecodeString(value)
+		field = tarantool.String(raw)
+	default:
+		field = tarantool.String(value)
+	}
+	return
+}
+
+// NUM fields are unsigned, so both signed and unsigned values are accepted
+func parseInt(value string, bitSize int) (i int64, err error) {
+	i, err = strconv.ParseInt(value, 10, bitSize)
+	if err == nil {
+		return
+	}
+	u, uerr := strconv.ParseUint(value, 10, bitSize)
+	if uerr == nil {
+		i, err = int64(u), nil
+	}
+	return
+}
+
+func parseFields(args []string, fieldHint func(i int) string) (fields []tarantool.TupleField, err error) {
+	fields = make([]tarantool.TupleField, len(args))
+	for i, arg := range args {
+		fields[i], err = parseField(arg, fieldHint(i))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// parseHints parses comma separated list of hints, empty items are guessed
+func parseHints(list string) (fieldHints []string, err error) {
+	if list == "" {
+		return
+	}
+	fieldHints = strings.Split(list, ",")
+	for i, hint := range fieldHints {
+		hint = strings.TrimSpace(hint)
+		if hint == "-" {
+			hint = ""
+		}
+		if hint != "" && !hints[hint] {
+			err = fmt.Errorf("Unknown field type %q", hint)
+			return
+		}
+		fieldHints[i] = hint
+	}
+	return
+}
+
+// decodeField converts field into Go value according to its hint.
+// Fields without hint are guessed: printable ones are strings,
+// 1, 4 and 8 bytes long are numbers, everything else is raw bytes.
+// Numbers are unsigned as tarantool NUM and NUM64 are.
+func decodeField(field []byte, hint string) interface{} {
+	switch {
+	case hint == "i8" && len(field) == 1, hint == "" && len(field) == 1 && !printable(field):
+		return uint64(field[0])
+	case hint == "i32" && len(field) == 4:
+		return uint64(binary.LittleEndian.Uint32(field))
+	case hint == "i64" && len(field) == 8:
+		return binary.LittleEndian.Uint64(field)
+	case hint == "s", hint == "" && printable(field):
+		return string(field)
+	case hint == "" && len(field) == 4:
+		return uint64(binary.LittleEndian.Uint32(field))
+	case hint == "" && len(field) == 8:
+		return binary.LittleEndian.Uint64(field)
+	}
+	return field
+}
+
+func formatField(field []byte, hint string) string {
+	switch val := decodeField(field, hint).(type) {
+	case string:
+		return strconv.Quote(val)
+	case []byte:
+		return "x:" + hex.EncodeToString(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func formatTuple(tuple [][]byte, space *tarantool.SpaceSchema) string {
+	formatted := make([]string, len(tuple))
+	for i, field := range tuple {
+		formatted[i] = formatField(field, fieldHint(space, int32(i)))
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
+func fieldHint(space *tarantool.SpaceSchema, fieldNo int32) string {
+	if space == nil {
+		return ""
+	}
+	return schemaHints[space.FieldType(fieldNo)]
+}
+
+func keyHint(space *tarantool.SpaceSchema, indexNo int32, i int) string {
+	if space == nil {
+		return ""
+	}
+	index := space.Index(indexNo)
+	if index == nil || i >= len(index.KeyFields) {
+		return ""
+	}
+	return schemaHints[index.KeyFields[i].Type]
+}
+
+func printable(field []byte) bool {
+	if !utf8.Valid(field) {
+		return false
+	}
+	for _, r := range string(field) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
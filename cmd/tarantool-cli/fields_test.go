@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+func TestParseField(t *testing.T) {
+	cases := []struct {
+		arg   string
+		hint  string
+		field tarantool.TupleField
+	}{
+		{"i32:1", "", tarantool.Int32(1)},
+		{"i8:18", "", tarantool.Int8(18)},
+		{"i64:-5", "", tarantool.Int64(-5)},
+		{"s:Peter", "", tarantool.String("Peter")},
+		{"Peter", "", tarantool.String("Peter")},
+		{"1", "i32", tarantool.Int32(1)},
+		{"s:1", "i32", tarantool.String("1")},
+		{"x:0102", "", tarantool.String("\x01\x02")},
+		{"http://host", "", tarantool.String("http://host")},
+	}
+	for _, c := range cases {
+		field, err := parseField(c.arg, c.hint)
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+			continue
+		}
+		if field != c.field {
+			t.Errorf("%q should be parsed into %#v not %#v", c.arg, c.field, field)
+		}
+	}
+
+	if _, err := parseField("i8:300", ""); err == nil {
+		t.Errorf("Error expected for i8 overflow")
+	}
+}
+
+func TestFormatTuple(t *testing.T) {
+	tuple := [][]byte{}
+	for _, field := range []tarantool.TupleField{tarantool.Int32(1), tarantool.String("Peter"), tarantool.Int8(18)} {
+		buf := new(bytes.Buffer)
+		field.Pack(buf)
+		tuple = append(tuple, buf.Bytes()[1:])
+	}
+
+	formatted := formatTuple(tuple, nil)
+	if formatted != `[1, "Peter", 18]` {
+		t.Errorf("Unexpected tuple format %s", formatted)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	args, err := splitArgs(`insert 0 i32:1 "s:Peter Pan"  'x'`)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	expected := []string{"insert", "0", "i32:1", "s:Peter Pan", "x"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Args should be %q not %q", expected, args)
+	}
+
+	if _, err = splitArgs(`call "box.dostring`); err == nil {
+		t.Errorf("Error expected for unterminated quote")
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	tuple := [][]byte{
+		[]byte("i32:1"),
+		{1, 2, 3},
+		{0, 1},
+		{18},
+		{5, 0, 0, 0},
+		{5, 0, 0, 0, 0, 0, 0, 0},
+		{42, 0, 0, 0},
+	}
+	hints := []string{"", "", "x", "", "", "", "i32"}
+	hintOf := func(i int) string { return hints[i] }
+
+	for _, format := range []string{"jsonl", "csv"} {
+		buf := new(bytes.Buffer)
+		exp := &exporter{hints: hints}
+		read := jsonReader(buf)
+		if format == "csv" {
+			exp.csv = csv.NewWriter(buf)
+			read = csvReader(buf)
+		} else {
+			exp.json = json.NewEncoder(buf)
+		}
+		err := exp.write(tuple)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if exp.csv != nil {
+			exp.csv.Flush()
+		}
+
+		values, err := read()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		packed, err := packRecord(values, hintOf)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		for i, field := range packed {
+			buf := new(bytes.Buffer)
+			field.Pack(buf)
+			if !bytes.Equal(buf.Bytes()[1:], tuple[i]) {
+				t.Errorf("%s field %d should be imported as %v not %v", format, i, tuple[i], buf.Bytes()[1:])
+			}
+		}
+	}
+}
+
+func TestImportParallel(t *testing.T) {
+	err := runImport(nil, nil, []string{"-parallel", "0"})
+	if err == nil {
+		t.Errorf("Import without workers should be rejected")
+	}
+}
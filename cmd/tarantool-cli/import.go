@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+// record is a parsed input line with its position in input
+type record struct {
+	no    int64
+	tuple []tarantool.TupleField
+}
+
+type result struct {
+	no  int64
+	err error
+}
+
+func runImport(conn *tarantool.Connection, schema *tarantool.Schema, args []string) (err error) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	spaceNo := flags.Int("space", 0, "space to import into")
+	fieldList := flags.String("fields", "", "comma separated field types (i8, i32, i64, s, x), taken from -config by default")
+	format := flags.String("format", "jsonl", "input format, jsonl or csv")
+	mode := flags.String("mode", "insert", "insert, add or replace")
+	parallel := flags.Int("parallel", 16, "requests in flight")
+	progress := flags.Int("progress", 10000, "report progress every N tuples")
+	checkpoint := flags.String("checkpoint", "", "file to store progress in and resume from")
+	flags.Parse(args)
+	if *parallel < 1 {
+		return fmt.Errorf("Usage: -parallel should be at least 1, not %d", *parallel)
+	}
+
+	fieldHints, err := parseHints(*fieldList)
+	if err != nil {
+		return
+	}
+	spaceSchema := schemaSpace(schema, int32(*spaceNo))
+	hintOf := func(i int) string {
+		if i < len(fieldHints) && fieldHints[i] != "" {
+			return fieldHints[i]
+		}
+		return fieldHint(spaceSchema, int32(i))
+	}
+
+	space := conn.Space(int32(*spaceNo))
+	var write func([]tarantool.TupleField, bool) ([][][]byte, error)
+	switch *mode {
+	case "insert":
+		write = space.Insert
+	case "add":
+		write = space.Add
+	case "replace":
+		write = space.Replace
+	default:
+		return fmt.Errorf("Unknown mode %q", *mode)
+	}
+
+	in := io.Reader(os.Stdin)
+	if flags.NArg() > 0 {
+		var file *os.File
+		file, err = os.Open(flags.Arg(0))
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		in = file
+	}
+	var read func() ([]interface{}, error)
+	switch *format {
+	case "jsonl":
+		read = jsonReader(in)
+	case "csv":
+		read = csvReader(in)
+	default:
+		return fmt.Errorf("Unknown format %q", *format)
+	}
+
+	resumeFrom, err := readCheckpoint(*checkpoint)
+	if err != nil {
+		return
+	}
+
+	records := make(chan record)
+	results := make(chan result)
+	var workers sync.WaitGroup
+	for i := 0; i < *parallel; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for rec := range records {
+				_, err := write(rec.tuple, false)
+				results <- result{rec.no, err}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	// Reader stops on its own error or when import fails
+	stop := make(chan struct{})
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(records)
+		for no := int64(0); ; no++ {
+			values, err := read()
+			if err == io.EOF {
+				readErr <- nil
+				return
+			}
+			if err == nil && no < resumeFrom {
+				continue
+			}
+			var tuple []tarantool.TupleField
+			if err == nil {
+				tuple, err = packRecord(values, hintOf)
+			}
+			if err != nil {
+				readErr <- fmt.Errorf("Record %d: %s", no+1, err.Error())
+				return
+			}
+			select {
+			case records <- record{no, tuple}:
+			case <-stop:
+				readErr <- nil
+				return
+			}
+		}
+	}()
+
+	// Checkpoint is the number of leading records which are all written,
+	// records after it may be written too when import stops
+	var (
+		done    = map[int64]bool{}
+		written = resumeFrom
+		count   = 0
+		started = time.Now()
+	)
+	for res := range results {
+		if res.err != nil {
+			if err == nil {
+				err = fmt.Errorf("Record %d: %s", res.no+1, res.err.Error())
+				close(stop)
+			}
+			continue
+		}
+		done[res.no] = true
+		for done[written] {
+			delete(done, written)
+			written++
+		}
+		count++
+		if *progress > 0 && count%*progress == 0 {
+			reportProgress(count, started)
+			writeCheckpoint(*checkpoint, written)
+		}
+	}
+	if rerr := <-readErr; err == nil {
+		err = rerr
+	}
+
+	reportProgress(count, started)
+	if cerr := writeCheckpoint(*checkpoint, written); err == nil {
+		err = cerr
+	}
+	return
+}
+
+func packRecord(values []interface{}, hintOf func(i int) string) (tuple []tarantool.TupleField, err error) {
+	tuple = make([]tarantool.TupleField, len(values))
+	for i, value := range values {
+		hint := hintOf(i)
+		switch val := value.(type) {
+		case json.Number:
+			if hint == "" {
+				hint = "i32"
+				if _, ierr := parseInt(val.String(), 32); ierr != nil {
+					hint = "i64"
+				}
+			}
+			tuple[i], err = packValue(val.String(), hint)
+		case string:
+			if hint == "" {
+				tuple[i], err = parseField(val, "")
+			} else {
+				tuple[i], err = packValue(val, hint)
+			}
+		default:
+			err = fmt.Errorf("Field %d should be a string or a number, not %v", i, value)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func jsonReader(in io.Reader) func() ([]interface{}, error) {
+	decoder := json.NewDecoder(in)
+	decoder.UseNumber()
+	return func() (values []interface{}, err error) {
+		err = decoder.Decode(&values)
+		return
+	}
+}
+
+func csvReader(in io.Reader) func() ([]interface{}, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	return func() (values []interface{}, err error) {
+		record, err := reader.Read()
+		if err != nil {
+			return
+		}
+		values = make([]interface{}, len(record))
+		for i, field := range record {
+			values[i] = field
+		}
+		return
+	}
+}
+
+func reportProgress(count int, started time.Time) {
+	rate := float64(count) / time.Since(started).Seconds()
+	fmt.Fprintf(os.Stderr, "imported %d tuples, %.0f tuples/s\n", count, rate)
+}
+
+func readCheckpoint(path string) (written int64, err error) {
+	if path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return
+	}
+	written, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return
+}
+
+// writeCheckpoint replaces checkpoint atomically, so it is never half written
+func writeCheckpoint(path string, written int64) (err error) {
+	if path == "" {
+		return
+	}
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, []byte(strconv.FormatInt(written, 10)+"\n"), 0644)
+	if err != nil {
+		return
+	}
+	err = os.Rename(tmp, path)
+	return
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const maxHistory = 1000
+
+// lineReader reads commands with history and emacs-like line editing
+// when stdin is a terminal, and plain lines otherwise.
+type lineReader struct {
+	prompt      string
+	in          *bufio.Reader
+	out         io.Writer
+	history     []string
+	historyFile string
+}
+
+func newLineReader(prompt, historyFile string) (lr *lineReader) {
+	lr = &lineReader{prompt, bufio.NewReader(os.Stdin), os.Stdout, nil, historyFile}
+	lr.loadHistory()
+	return
+}
+
+// ReadLine returns io.EOF on Ctrl-D or end of input
+func (lr *lineReader) ReadLine() (line string, err error) {
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Fprint(lr.out, lr.prompt)
+		line, err = lr.in.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		line = strings.TrimRight(line, "\r\n")
+	} else {
+		line, err = lr.edit()
+		restore()
+		fmt.Fprint(lr.out, "\r\n")
+	}
+	if err == nil {
+		lr.addHistory(line)
+	}
+	return
+}
+
+func (lr *lineReader) edit() (line string, err error) {
+	var (
+		buf     []rune
+		pos     int
+		current = len(lr.history)
+		saved   []rune
+	)
+	lr.redraw(buf, pos)
+
+	for {
+		var r rune
+		r, _, err = lr.in.ReadRune()
+		if err != nil {
+			return
+		}
+
+		switch r {
+		case '\r', '\n':
+			return string(buf), nil
+		case 1: // Ctrl-A
+			pos = 0
+		case 5: // Ctrl-E
+			pos = len(buf)
+		case 2: // Ctrl-B
+			if pos > 0 {
+				pos--
+			}
+		case 6: // Ctrl-F
+			if pos < len(buf) {
+				pos++
+			}
+		case 3: // Ctrl-C drops the line
+			fmt.Fprint(lr.out, "^C\r\n")
+			buf, pos = nil, 0
+		case 4: // Ctrl-D
+			if len(buf) == 0 {
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case 11: // Ctrl-K
+			buf = buf[:pos]
+		case 21: // Ctrl-U
+			buf, pos = buf[pos:], 0
+		case 23: // Ctrl-W
+			start := pos
+			for start > 0 && buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && buf[start-1] != ' ' {
+				start--
+			}
+			buf, pos = append(buf[:start], buf[pos:]...), start
+		case 8, 127: // Backspace
+			if pos > 0 {
+				buf, pos = append(buf[:pos-1], buf[pos:]...), pos-1
+			}
+		case 27: // Escape sequences: arrows, home, end, delete
+			var seq []rune
+			seq, err = lr.escape()
+			if err != nil {
+				return
+			}
+			switch string(seq) {
+			case "[A", "OA":
+				if current > 0 {
+					if current == len(lr.history) {
+						saved = buf
+					}
+					current--
+					buf = []rune(lr.history[current])
+					pos = len(buf)
+				}
+			case "[B", "OB":
+				if current < len(lr.history) {
+					current++
+					if current == len(lr.history) {
+						buf = saved
+					} else {
+						buf = []rune(lr.history[current])
+					}
+					pos = len(buf)
+				}
+			case "[C", "OC":
+				if pos < len(buf) {
+					pos++
+				}
+			case "[D", "OD":
+				if pos > 0 {
+					pos--
+				}
+			case "[H", "OH", "[1~":
+				pos = 0
+			case "[F", "OF", "[4~":
+				pos = len(buf)
+			case "[3~":
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if unicode.IsPrint(r) {
+				buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+				pos++
+			}
+		}
+		lr.redraw(buf, pos)
+	}
+}
+
+// escape reads the rest of escape sequence after ESC
+func (lr *lineReader) escape() (seq []rune, err error) {
+	for {
+		var r rune
+		r, _, err = lr.in.ReadRune()
+		if err != nil {
+			return
+		}
+		seq = append(seq, r)
+		if len(seq) > 1 && (r == '~' || unicode.IsLetter(r)) || len(seq) > 4 {
+			return
+		}
+	}
+}
+
+func (lr *lineReader) redraw(buf []rune, pos int) {
+	fmt.Fprintf(lr.out, "\r%s%s\x1b[K", lr.prompt, string(buf))
+	if back := len(buf) - pos; back > 0 {
+		fmt.Fprintf(lr.out, "\x1b[%dD", back)
+	}
+}
+
+func (lr *lineReader) addHistory(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || len(lr.history) > 0 && lr.history[len(lr.history)-1] == line {
+		return
+	}
+	lr.history = append(lr.history, line)
+	if len(lr.history) > maxHistory {
+		lr.history = lr.history[len(lr.history)-maxHistory:]
+	}
+
+	if lr.historyFile == "" {
+		return
+	}
+	file, err := os.OpenFile(lr.historyFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
+
+func (lr *lineReader) loadHistory() {
+	if lr.historyFile == "" {
+		return
+	}
+	file, err := os.Open(lr.historyFile)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lr.history = append(lr.history, scanner.Text())
+	}
+	if len(lr.history) > maxHistory {
+		lr.history = lr.history[len(lr.history)-maxHistory:]
+	}
+}
+
+func (lr *lineReader) printHistory() {
+	for i, line := range lr.history {
+		fmt.Fprintf(lr.out, "%5d  %s\n", i+1, line)
+	}
+}
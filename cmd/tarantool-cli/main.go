@@ -0,0 +1,138 @@
+// Tarantool-cli is an interactive shell for tarantool binary protocol.
+//
+//	> tarantool-cli -addr localhost:33013 -config tarantool.cfg
+//	localhost:33013> insert 0 i32:1 s:Peter i8:18 s:janitor
+//	[1, "Peter", 18, "janitor"]
+//	(1 tuples)
+//
+// Type help in the shell to list commands. Passing a command as arguments
+// runs it and exits:
+//
+//	> tarantool-cli -addr localhost:33013 select 0 0 i32:1
+//
+// Spaces are exported to and imported from JSON Lines or CSV
+// with export and import commands:
+//
+//	> tarantool-cli -config tarantool.cfg export -space 0 -o space0.jsonl
+//	> tarantool-cli -config tarantool.cfg import -space 0 -checkpoint space0.pos space0.jsonl
+//
+// Copy command moves a space to another instance or space:
+//
+//	> tarantool-cli -config tarantool.cfg copy -space 0 -to replica:33013 -rate 5000 -verify
+//
+// Check command compares a space with a replica and prints differences:
+//
+//	> tarantool-cli -config tarantool.cfg check -space 0 -with replica:33013
+//
+// Run them with -h to see all options.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:33013", "tarantool primary port address")
+	config := flag.String("config", "", "tarantool.cfg to type fields by schema")
+	history := flag.String("history", defaultHistoryFile(), "history file, empty to disable")
+	flag.Parse()
+
+	var schema *tarantool.Schema
+	if *config != "" {
+		var err error
+		schema, err = tarantool.LoadConfig(*config)
+		if err != nil {
+			fatal(err)
+		}
+	}
+
+	conn, err := tarantool.Connect(*addr)
+	if err != nil {
+		fatal(err)
+	}
+
+	switch flag.Arg(0) {
+	case "export":
+		err = runExport(conn, schema, flag.Args()[1:])
+	case "import":
+		err = runImport(conn, schema, flag.Args()[1:])
+	case "copy":
+		err = runCopy(conn, schema, flag.Args()[1:])
+	case "check":
+		err = runCheck(conn, schema, flag.Args()[1:])
+	default:
+		runShell(conn, schema, *addr, *history)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func runShell(conn *tarantool.Connection, schema *tarantool.Schema, addr, history string) {
+	sh := newShell(conn, schema, os.Stdout)
+
+	if flag.NArg() > 0 {
+		err := sh.exec(strings.Join(quoteArgs(flag.Args()), " "))
+		if err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	lr := newLineReader(addr+"> ", history)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fatal(err)
+		}
+
+		switch strings.TrimSpace(line) {
+		case "quit", "exit":
+			return
+		case "history":
+			lr.printHistory()
+			continue
+		}
+
+		err = sh.exec(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		}
+	}
+}
+
+// quoteArgs keeps shell-quoted arguments with spaces together
+func quoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t'") {
+			quoted[i] = `"` + arg + `"`
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return quoted
+}
+
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".tarantool_cli_history")
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err.Error())
+	os.Exit(1)
+}
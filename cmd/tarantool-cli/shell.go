@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fl00r/go-tarantool"
+)
+
+const usage = `Fields are strings unless hinted (i8:, i32:, i64:, s:, x: for hex)
+or typed by schema from -config.
+
+  select SPACE INDEX [KEY...]        select by key, see "set limit"
+  insert SPACE FIELD...              insert or replace tuple
+  add SPACE FIELD...                 insert tuple, fail if it exists
+  replace SPACE FIELD...             replace tuple, fail if it doesn't exist
+  update SPACE KEY... set FIELDNO OP [VALUE]...
+                                     OP is one of = + & ^ | # (delete) ! (insert)
+  delete SPACE KEY...                delete tuple by primary key
+  call PROC [ARG...]                 call lua procedure
+  ping                               ping server
+  set offset|limit N                 select offset and limit
+  history                            show history
+  help                               show this help
+  quit                               exit`
+
+var updateOps = map[string]int8{
+	"=": tarantool.OpEq,
+	"+": tarantool.OpAdd,
+	"&": tarantool.OpAnd,
+	"^": tarantool.OpXor,
+	"|": tarantool.OpOr,
+	"#": tarantool.OpDelete,
+	"!": tarantool.OpPrepend,
+}
+
+type shell struct {
+	conn   *tarantool.Connection
+	schema *tarantool.Schema
+	out    io.Writer
+	offset int32
+	limit  int32
+}
+
+func newShell(conn *tarantool.Connection, schema *tarantool.Schema, out io.Writer) *shell {
+	return &shell{conn, schema, out, 0, 100}
+}
+
+// exec runs one command line. Errors are returned to be printed,
+// they never break the shell.
+func (sh *shell) exec(line string) (err error) {
+	args, err := splitArgs(line)
+	if err != nil || len(args) == 0 {
+		return
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "select":
+		err = sh.selectCmd(args)
+	case "insert", "add", "replace":
+		err = sh.insertCmd(command, args)
+	case "update":
+		err = sh.updateCmd(args)
+	case "delete":
+		err = sh.deleteCmd(args)
+	case "call":
+		err = sh.callCmd(args)
+	case "ping":
+		_, err = sh.conn.Space(0).Ping()
+		if err == nil {
+			fmt.Fprintln(sh.out, "pong")
+		}
+	case "set":
+		err = sh.setCmd(args)
+	case "help":
+		fmt.Fprintln(sh.out, usage)
+	default:
+		err = fmt.Errorf("Unknown command %q, try help", command)
+	}
+	return
+}
+
+func (sh *shell) selectCmd(args []string) (err error) {
+	if len(args) < 2 {
+		return fmt.Errorf("Usage: select SPACE INDEX [KEY...]")
+	}
+	spaceNo, err := parseNo(args[0])
+	if err != nil {
+		return
+	}
+	indexNo, err := parseNo(args[1])
+	if err != nil {
+		return
+	}
+	schema := sh.spaceSchema(spaceNo)
+	key, err := parseFields(args[2:], func(i int) string { return keyHint(schema, indexNo, i) })
+	if err != nil {
+		return
+	}
+
+	tuples, err := sh.conn.Space(spaceNo).Select(indexNo, sh.offset, sh.limit, key)
+	if err != nil {
+		return
+	}
+	sh.print(spaceNo, tuples)
+	return
+}
+
+func (sh *shell) insertCmd(command string, args []string) (err error) {
+	if len(args) < 2 {
+		return fmt.Errorf("Usage: %s SPACE FIELD...", command)
+	}
+	spaceNo, err := parseNo(args[0])
+	if err != nil {
+		return
+	}
+	schema := sh.spaceSchema(spaceNo)
+	tuple, err := parseFields(args[1:], func(i int) string { return fieldHint(schema, int32(i)) })
+	if err != nil {
+		return
+	}
+
+	space := sh.conn.Space(spaceNo)
+	var tuples [][][]byte
+	switch command {
+	case "add":
+		tuples, err = space.Add(tuple, true)
+	case "replace":
+		tuples, err = space.Replace(tuple, true)
+	default:
+		tuples, err = space.Insert(tuple, true)
+	}
+	if err != nil {
+		return
+	}
+	sh.print(spaceNo, tuples)
+	return
+}
+
+func (sh *shell) updateCmd(args []string) (err error) {
+	set := len(args)
+	for i, arg := range args {
+		if arg == "set" {
+			set = i
+			break
+		}
+	}
+	if len(args) < 2 || set < 2 || set == len(args) {
+		return fmt.Errorf("Usage: update SPACE KEY... set FIELDNO OP [VALUE]...")
+	}
+	spaceNo, err := parseNo(args[0])
+	if err != nil {
+		return
+	}
+	schema := sh.spaceSchema(spaceNo)
+	key, err := parseFields(args[1:set], func(i int) string { return keyHint(schema, 0, i) })
+	if err != nil {
+		return
+	}
+
+	ops := []tarantool.UpdOp{}
+	rest := args[set+1:]
+	for len(rest) > 0 {
+		if len(rest) < 2 {
+			return fmt.Errorf("Update op should be FIELDNO OP [VALUE]")
+		}
+		op := tarantool.UpdOp{}
+		op.FieldNo, err = parseNo(rest[0])
+		if err != nil {
+			return
+		}
+		opName := rest[1]
+		opCode, ok := updateOps[opName]
+		if !ok {
+			return fmt.Errorf("Unknown update op %q", opName)
+		}
+		op.OpCode = opCode
+		rest = rest[2:]
+
+		// Delete has no argument, but the protocol still wants a field
+		if opCode == tarantool.OpDelete {
+			op.Field = tarantool.String("")
+		} else {
+			if len(rest) == 0 {
+				return fmt.Errorf("Update op %d %s needs a value", op.FieldNo, opName)
+			}
+			op.Field, err = parseField(rest[0], fieldHint(schema, op.FieldNo))
+			if err != nil {
+				return
+			}
+			rest = rest[1:]
+		}
+		ops = append(ops, op)
+	}
+
+	tuples, err := sh.conn.Space(spaceNo).Update(key, true, ops...)
+	if err != nil {
+		return
+	}
+	sh.print(spaceNo, tuples)
+	return
+}
+
+func (sh *shell) deleteCmd(args []string) (err error) {
+	if len(args) < 2 {
+		return fmt.Errorf("Usage: delete SPACE KEY...")
+	}
+	spaceNo, err := parseNo(args[0])
+	if err != nil {
+		return
+	}
+	schema := sh.spaceSchema(spaceNo)
+	key, err := parseFields(args[1:], func(i int) string { return keyHint(schema, 0, i) })
+	if err != nil {
+		return
+	}
+
+	tuples, err := sh.conn.Space(spaceNo).Delete(key, true)
+	if err != nil {
+		return
+	}
+	sh.print(spaceNo, tuples)
+	return
+}
+
+func (sh *shell) callCmd(args []string) (err error) {
+	if len(args) < 1 {
+		return fmt.Errorf("Usage: call PROC [ARG...]")
+	}
+	callArgs, err := parseFields(args[1:], func(i int) string { return "" })
+	if err != nil {
+		return
+	}
+
+	tuples, err := sh.conn.Space(0).Call(args[0], true, callArgs...)
+	if err != nil {
+		return
+	}
+	// Procedure results don't belong to any space
+	sh.print(-1, tuples)
+	return
+}
+
+func (sh *shell) setCmd(args []string) (err error) {
+	if len(args) != 2 {
+		return fmt.Errorf("Usage: set offset|limit N")
+	}
+	n, err := parseNo(args[1])
+	if err != nil {
+		return
+	}
+	switch args[0] {
+	case "offset":
+		sh.offset = n
+	case "limit":
+		sh.limit = n
+	default:
+		err = fmt.Errorf("Unknown option %q", args[0])
+	}
+	return
+}
+
+func (sh *shell) spaceSchema(spaceNo int32) *tarantool.SpaceSchema {
+	return schemaSpace(sh.schema, spaceNo)
+}
+
+func (sh *shell) print(spaceNo int32, tuples [][][]byte) {
+	schema := sh.spaceSchema(spaceNo)
+	for _, tuple := range tuples {
+		fmt.Fprintln(sh.out, formatTuple(tuple, schema))
+	}
+	fmt.Fprintf(sh.out, "(%d tuples)\n", len(tuples))
+}
+
+func parseNo(arg string) (no int32, err error) {
+	i, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil {
+		err = fmt.Errorf("Number expected, got %q", arg)
+		return
+	}
+	no = int32(i)
+	return
+}
+
+// splitArgs splits line by spaces, single and double quotes group words
+func splitArgs(line string) (args []string, err error) {
+	var (
+		arg    strings.Builder
+		quote  rune
+		inWord bool
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, arg.String())
+				arg.Reset()
+				inWord = false
+			}
+		default:
+			arg.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		err = fmt.Errorf("Unterminated %c quote", quote)
+		return
+	}
+	if inWord {
+		args = append(args, arg.String())
+	}
+	return
+}
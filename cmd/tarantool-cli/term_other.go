@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"errors"
+)
+
+// Line editing is supported on linux only, other systems read plain lines
+func makeRaw(fd int) (restore func(), err error) {
+	err = errors.New("raw terminal mode is not supported")
+	return
+}
@@ -0,0 +1,92 @@
+package tarantool
+
+import (
+	"fmt"
+	"time"
+)
+
+type CopyOptions struct {
+	IndexNo int32 // TREE index to walk, primary one by default
+	// Key fields of the index, {0} by default
+	KeyFields []int32
+	// Tuples per box.select_range request, 1000 by default
+	BatchSize int32
+	// Transform may change tuple before writing it, nil tuple is skipped
+	Transform func(tuple [][]byte) ([][]byte, error)
+	// Rate limits written tuples per second, zero means no limit
+	Rate int
+	// Verify compares space sizes after copy, destination should contain
+	// exactly the copied tuples
+	Verify bool
+}
+
+type CopyStats struct {
+	Read    int64
+	Written int64
+	Skipped int64
+	// Space sizes are known when copy is verified
+	SourceCount int64
+	DestCount   int64
+}
+
+// Copy streams every tuple of src space into dst space in index order.
+// Tuples are written with Insert, so existing ones are replaced.
+// Stats are returned even on error to show how far copy went.
+func Copy(src, dst *Space, opts CopyOptions) (stats *CopyStats, err error) {
+	stats = new(CopyStats)
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 1000
+	}
+
+	started := time.Now()
+	iter := src.Iterator(IteratorOptions{IndexNo: opts.IndexNo, KeyFields: opts.KeyFields, BatchSize: opts.BatchSize})
+	for iter.Next() {
+		tuple := iter.Tuple()
+		stats.Read++
+		if opts.Transform != nil {
+			tuple, err = opts.Transform(tuple)
+			if err != nil {
+				return
+			}
+			if tuple == nil {
+				stats.Skipped++
+				continue
+			}
+		}
+
+		if opts.Rate > 0 {
+			due := started.Add(time.Duration(stats.Written) * time.Second / time.Duration(opts.Rate))
+			time.Sleep(time.Until(due))
+		}
+		_, err = dst.Insert(rawTuple(tuple), false)
+		if err != nil {
+			return
+		}
+		stats.Written++
+	}
+	err = iter.Err()
+	if err != nil || !opts.Verify {
+		return
+	}
+
+	stats.SourceCount, err = src.Len()
+	if err != nil {
+		return
+	}
+	stats.DestCount, err = dst.Len()
+	if err != nil {
+		return
+	}
+	if stats.DestCount != stats.SourceCount-stats.Skipped {
+		err = fmt.Errorf("Copy verification failed: %d tuples in source, %d skipped, %d in destination", stats.SourceCount, stats.Skipped, stats.DestCount)
+	}
+	return
+}
+
+func rawTuple(tuple [][]byte) (fields []TupleField) {
+	fields = make([]TupleField, len(tuple))
+	for i, field := range tuple {
+		fields[i] = String(field)
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	src := conn.Space(0)
+	dst := conn.Space(1)
+
+	for i := 1; i <= 3; i++ {
+		_, err := src.Insert([]TupleField{Int32(i), String("Peter"), Int8(18), String("janitor")}, false)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+	}
+
+	// Space 1 is keyed by (STR, NUM), so fields are swapped, Peter 1 is skipped
+	stats, err := Copy(src, dst, CopyOptions{
+		BatchSize: 2,
+		Verify:    true,
+		Transform: func(tuple [][]byte) ([][]byte, error) {
+			if tuple[0][0] == 1 {
+				return nil, nil
+			}
+			return [][]byte{tuple[1], tuple[0]}, nil
+		},
+	})
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if stats.Read != 3 || stats.Written != 2 || stats.Skipped != 1 {
+		t.Errorf("3 tuples should be read and 2 written, not %+v", stats)
+	}
+
+	for i := 1; i <= 3; i++ {
+		src.Delete([]TupleField{Int32(i)}, false)
+		dst.Delete([]TupleField{String("Peter"), Int32(i)}, false)
+	}
+}
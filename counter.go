@@ -0,0 +1,134 @@
+package tarantool
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var counterProc = Builtins.Register("go_tarantool_counter", 1, `
+function go_tarantool_counter(space, field, width, op, delta, ...)
+	space, field, width = tonumber(space), tonumber(field), tonumber(width)
+	local key = {...}
+	local k = key
+	if #key == 1 then
+		k = key[1]
+	end
+	local tuple = box.update(space, k, op .. 'p', field, delta)
+	if tuple ~= nil then
+		return tuple
+	end
+	local zero = box.pack('i', 0)
+	if width == 8 then
+		zero = box.pack('l', 0)
+	end
+	local fields = {}
+	for i, part in ipairs(key) do
+		fields[i] = part
+	end
+	for i = #key + 1, field do
+		fields[i] = zero
+	end
+	fields[field + 1] = delta
+	local ok, inserted = pcall(box.insert, space, unpack(fields))
+	if ok then
+		return inserted
+	end
+	-- someone has inserted it meanwhile
+	return box.update(space, k, op .. 'p', field, delta)
+end`).Procedure("go_tarantool_counter")
+
+// Counter is a number in a field of tuple updated atomically with OpAdd.
+// Missing tuple is created on the first change with key fields first,
+// then zeroes up to the counter field.
+type Counter struct {
+	space   *Space
+	key     []TupleField
+	fieldNo int32
+	wide    bool
+}
+
+// Counter binds a counter to field of tuple with primary key,
+// wide counters are 8 bytes long, 4 bytes otherwise
+func (space *Space) Counter(key []TupleField, fieldNo int32, wide bool) *Counter {
+	return &Counter{space, key, fieldNo, wide}
+}
+
+// Incr adds delta to counter and returns the new value
+func (counter *Counter) Incr(delta int64) (value int64, err error) {
+	arg := counter.pack(delta)
+	tuples, err := counter.space.Update(counter.key, true, UpdOp{counter.fieldNo, OpAdd, arg})
+	if err != nil {
+		return
+	}
+	if len(tuples) == 0 {
+		tuples, err = counter.upsert("+", arg)
+		if err != nil {
+			return
+		}
+	}
+	value, err = counter.value(tuples)
+	return
+}
+
+func (counter *Counter) Decr(delta int64) (value int64, err error) {
+	value, err = counter.Incr(-delta)
+	return
+}
+
+// Get returns 0 for missing counter
+func (counter *Counter) Get() (value int64, err error) {
+	tuples, err := counter.space.Select(0, 0, 1, counter.key)
+	if err != nil || len(tuples) == 0 {
+		return
+	}
+	value, err = counter.value(tuples)
+	return
+}
+
+func (counter *Counter) Reset() (err error) {
+	_, err = counter.upsert("=", counter.pack(0))
+	return
+}
+
+func (counter *Counter) upsert(op string, arg TupleField) (tuples [][][]byte, err error) {
+	width := "4"
+	if counter.wide {
+		width = "8"
+	}
+	args := []TupleField{
+		String(strconv.Itoa(int(counter.space.spaceNo))),
+		String(strconv.Itoa(int(counter.fieldNo))),
+		String(width),
+		String(op),
+		arg,
+	}
+	tuples, err = counterProc.Call(counter.space, append(args, counter.key...)...)
+	return
+}
+
+func (counter *Counter) pack(value int64) TupleField {
+	if counter.wide {
+		return Int64(value)
+	}
+	return Int32(value)
+}
+
+// value reads counter field as signed, so decremented below zero
+// counters stay negative
+func (counter *Counter) value(tuples [][][]byte) (value int64, err error) {
+	if len(tuples) == 0 || int(counter.fieldNo) >= len(tuples[0]) {
+		err = fmt.Errorf("Counter field %d is missing in %v", counter.fieldNo, tuples)
+		return
+	}
+	field := tuples[0][counter.fieldNo]
+	if counter.wide {
+		var val Int64
+		err = val.Unpack(field)
+		value = int64(val)
+	} else {
+		var val Int32
+		err = val.Unpack(field)
+		value = int64(val)
+	}
+	return
+}
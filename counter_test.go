@@ -0,0 +1,45 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(0)
+	key := []TupleField{Int32(1)}
+	defer space.Delete(key, false)
+
+	for _, wide := range []bool{false, true} {
+		space.Delete(key, false)
+		counter := space.Counter(key, 2, wide)
+
+		value, err := counter.Incr(5)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if value != 5 {
+			t.Errorf("Missing counter should be created with 5, not %d", value)
+		}
+
+		value, err = counter.Decr(7)
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+		if value != -2 {
+			t.Errorf("Counter should be decremented to -2, not %d", value)
+		}
+
+		err = counter.Reset()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+		value, err = counter.Get()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+		if value != 0 {
+			t.Errorf("Counter should be reset to 0, not %d", value)
+		}
+	}
+}
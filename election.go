@@ -0,0 +1,125 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// leader lease is checked against server clock, the same one Lock uses
+var leaderProc = Builtins.Register("go_tarantool_lock_owner", 1, `
+function go_tarantool_lock_owner(space, name)
+	local tuple = box.select(tonumber(space), 0, name)
+	if tuple ~= nil and box.unpack('l', tuple[3]) > math.floor(box.time() * 1000) then
+		return tuple[1]
+	end
+end`).Procedure("go_tarantool_lock_owner")
+
+// LeadershipEvent tells candidate that it became or stopped being a leader
+type LeadershipEvent struct {
+	Leader bool
+	// Fence of the lease, grows with every new leader
+	Fence int64
+}
+
+// Election is a campaign for a named leadership key. Leadership is a Lock
+// lease, so space should have the same layout as for Space.Lock.
+type Election struct {
+	lock   *Lock
+	events chan LeadershipEvent
+}
+
+// Election returns a new candidate, leader lease expires after ttl
+// unless renewed
+func (space *Space) Election(name string, ttl time.Duration) *Election {
+	return &Election{space.Lock(name, ttl), make(chan LeadershipEvent, 1)}
+}
+
+// Id is owner token of the candidate, see Leader
+func (election *Election) Id() string {
+	return election.lock.Token()
+}
+
+// Events receives leadership changes. It keeps only the latest event
+// if it is not read in time and is closed when Campaign returns.
+func (election *Election) Events() <-chan LeadershipEvent {
+	return election.events
+}
+
+func (election *Election) IsLeader() bool {
+	select {
+	case <-election.lock.Lost():
+		return false
+	default:
+		return true
+	}
+}
+
+// Leader returns id of the current leader, empty string if there is none
+func (election *Election) Leader() (id string, err error) {
+	lock := election.lock
+	res, err := leaderProc.Call(lock.space,
+		String(strconv.Itoa(int(lock.space.spaceNo))),
+		String(lock.name))
+	if err != nil || len(res) == 0 {
+		return
+	}
+	if len(res) != 1 || len(res[0]) != 1 {
+		err = fmt.Errorf("Unexpected leader result %v", res)
+		return
+	}
+	id = string(res[0][0])
+	return
+}
+
+// Campaign runs until ctx is done. Candidate waits for leadership,
+// holds it while lease is renewed and campaigns again when it is lost,
+// e.g. because connection is broken. Leadership is given up on return.
+// Campaign should be called once.
+func (election *Election) Campaign(ctx context.Context) error {
+	defer close(election.events)
+	lock := election.lock
+
+	for {
+		err := lock.Lock(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// tarantool is unreachable, wait a lease before retry
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(lock.ttl):
+			}
+			continue
+		}
+
+		fence := lock.Fence()
+		election.notify(LeadershipEvent{true, fence})
+		select {
+		case <-ctx.Done():
+			lock.Unlock()
+			election.notify(LeadershipEvent{false, fence})
+			return ctx.Err()
+		case <-lock.Lost():
+			election.notify(LeadershipEvent{false, fence})
+		}
+	}
+}
+
+// notify replaces unread event with a new one
+func (election *Election) notify(event LeadershipEvent) {
+	for {
+		select {
+		case election.events <- event:
+			return
+		default:
+		}
+		select {
+		case <-election.events:
+		default:
+		}
+	}
+}
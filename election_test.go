@@ -0,0 +1,83 @@
+package tarantool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestElection(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(2)
+	defer space.Delete([]TupleField{String("leader")}, false)
+
+	first := space.Election("leader", 300*time.Millisecond)
+	second := space.Election("leader", 300*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go first.Campaign(ctx)
+	event := <-first.Events()
+	if !event.Leader {
+		t.Fatalf("First candidate should become a leader")
+	}
+
+	secondCtx, secondCancel := context.WithCancel(context.Background())
+	defer func() {
+		secondCancel()
+		// wait until leadership is given up
+		for range second.Events() {
+		}
+	}()
+	go second.Campaign(secondCtx)
+	time.Sleep(100 * time.Millisecond)
+	if second.IsLeader() {
+		t.Errorf("Only one candidate should be a leader")
+	}
+	leader, err := second.Leader()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if leader != first.Id() {
+		t.Errorf("Leader should be %s, not %s", first.Id(), leader)
+	}
+
+	cancel()
+	event, ok := <-first.Events()
+	if !ok || event.Leader {
+		t.Errorf("Leadership should be lost on cancel")
+	}
+
+	select {
+	case event = <-second.Events():
+		if !event.Leader {
+			t.Errorf("Second candidate should become a leader")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Second candidate should become a leader")
+	}
+}
+
+func TestElectionLeader(t *testing.T) {
+	owner := ""
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		if req.ProcName() != "go_tarantool_lock_owner" {
+			t.Errorf("Leader should be checked by procedure, not %s", req.OpName())
+		}
+		if owner == "" {
+			return nil, nil
+		}
+		return [][][]byte{{[]byte(owner)}}, nil
+	})
+	election := conn.Space(2).Election("leader", time.Second)
+
+	leader, err := election.Leader()
+	if err != nil || leader != "" {
+		t.Errorf("Expired lease should have no leader, got %q %v", leader, err)
+	}
+	owner = "abc"
+	leader, err = election.Leader()
+	if err != nil || leader != "abc" {
+		t.Errorf("Leader should be abc, got %q %v", leader, err)
+	}
+}
@@ -0,0 +1,144 @@
+package tarantool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+type GetManyOptions struct {
+	IndexNo int32
+	// Fields of the index key, {0} by default for the primary index,
+	// they are required for other indexes
+	KeyFields []int32
+	// Keys per select, 100 by default
+	ChunkSize int
+	// Selects in flight, 4 by default
+	Parallelism int
+}
+
+// GetResult has all tuples matching key, a single one for unique indexes
+type GetResult struct {
+	Key    []TupleField
+	Tuples [][][]byte
+	Found  bool
+}
+
+// GetMany selects tuples by keys splitting them into chunks selected
+// concurrently. Results are in the order of keys, missing keys have
+// Found false.
+func (space *Space) GetMany(keys [][]TupleField, opts GetManyOptions) (results []GetResult, err error) {
+	if opts.KeyFields == nil {
+		if opts.IndexNo != 0 {
+			err = fmt.Errorf("Key fields of index %d are unknown", opts.IndexNo)
+			return
+		}
+		opts.KeyFields = []int32{0}
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 100
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 4
+	}
+
+	results = make([]GetResult, len(keys))
+	positions := map[string][]int{}
+	for i, key := range keys {
+		results[i].Key = key
+		var packed string
+		packed, err = packedKey(key)
+		if err != nil {
+			return
+		}
+		positions[packed] = append(positions[packed], i)
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	chunks := make(chan [][]TupleField)
+	for w := 0; w < opts.Parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				tuples, selectErr := space.Select(opts.IndexNo, 0, math.MaxInt32, chunk...)
+				mutex.Lock()
+				if selectErr != nil {
+					if err == nil {
+						err = selectErr
+					}
+					mutex.Unlock()
+					continue
+				}
+				for _, tuple := range tuples {
+					packed := rawKey(tupleKey(tuple, opts.KeyFields))
+					for _, i := range positions[packed] {
+						results[i].Tuples = append(results[i].Tuples, tuple)
+						results[i].Found = true
+					}
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	// duplicate keys are selected once
+	seen := map[string]bool{}
+	chunk := [][]TupleField{}
+	for _, key := range keys {
+		packed, _ := packedKey(key)
+		if seen[packed] {
+			continue
+		}
+		seen[packed] = true
+		chunk = append(chunk, key)
+		if len(chunk) == opts.ChunkSize {
+			chunks <- chunk
+			chunk = [][]TupleField{}
+		}
+	}
+	if len(chunk) > 0 {
+		chunks <- chunk
+	}
+	close(chunks)
+	wg.Wait()
+	return
+}
+
+// packedKey is key fields as they come in tuples, joined by rawKey
+func packedKey(key []TupleField) (packed string, err error) {
+	fields := make([][]byte, len(key))
+	for i, field := range key {
+		buffer := new(bytes.Buffer)
+		err = field.Pack(buffer)
+		if err != nil {
+			return
+		}
+		var size uint64
+		size, err = binary.ReadUvarint(buffer)
+		if err != nil {
+			return
+		}
+		if uint64(buffer.Len()) != size {
+			err = fmt.Errorf("Packed field has %d bytes, not %d", buffer.Len(), size)
+			return
+		}
+		fields[i] = buffer.Bytes()
+	}
+	packed = rawKey(fields)
+	return
+}
+
+func rawKey(fields [][]byte) string {
+	buffer := new(bytes.Buffer)
+	for _, field := range fields {
+		binary.Write(buffer, binary.LittleEndian, int32(len(field)))
+		buffer.Write(field)
+	}
+	return buffer.String()
+}
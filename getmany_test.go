@@ -0,0 +1,49 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestGetMany(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(12)
+	defer space.Truncate()
+
+	keys := [][]TupleField{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		space.Insert([]TupleField{String(name), String("owner " + name)}, false)
+		keys = append(keys, []TupleField{String(name)})
+	}
+	keys = append([][]TupleField{{String("missing")}}, keys...)
+	keys = append(keys, []TupleField{String("a")})
+
+	results, err := space.GetMany(keys, GetManyOptions{ChunkSize: 2})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if len(results) != 7 {
+		t.Fatalf("7 results expected, not %d", len(results))
+	}
+	if results[0].Found {
+		t.Errorf("Missing key should not be found")
+	}
+	for _, result := range results[1:] {
+		if !result.Found || len(result.Tuples) != 1 {
+			t.Errorf("Key %v should be found once, got %q", result.Key, result.Tuples)
+			continue
+		}
+		if string(result.Tuples[0][1]) != "owner "+string(result.Key[0].(String)) {
+			t.Errorf("Wrong tuple %q for key %v", result.Tuples[0], result.Key)
+		}
+	}
+}
+
+func TestPackedKey(t *testing.T) {
+	packed, err := packedKey([]TupleField{String("abc"), Int32(1)})
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if packed != rawKey([][]byte{[]byte("abc"), {1, 0, 0, 0}}) {
+		t.Errorf("Packed key should match tuple fields, got %q", packed)
+	}
+}
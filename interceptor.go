@@ -0,0 +1,200 @@
+package tarantool
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// Request is a request on its way to tarantool, Body is encoded
+// as box protocol describes it
+type Request struct {
+	Op      int32
+	SpaceNo int32
+	Body    []byte
+
+	ctx context.Context
+}
+
+// Handler sends request and decodes response
+type Handler func(req *Request) (tuples [][][]byte, err error)
+
+// Interceptor wraps requests, it may change request, result or error,
+// skip next handler or call it more than once
+type Interceptor func(req *Request, next Handler) (tuples [][][]byte, err error)
+
+// Use adds interceptors to all requests of connection,
+// the first one added is the outermost
+func (conn *Connection) Use(interceptors ...Interceptor) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.interceptors = append(conn.interceptors[:len(conn.interceptors):len(conn.interceptors)], interceptors...)
+}
+
+// WithContext returns a copy of space making requests with ctx.
+// Requests are not sent when ctx is done, but ones in flight are not
+// canceled, ctx is mostly for interceptors and tracing.
+func (space *Space) WithContext(ctx context.Context) *Space {
+	copied := *space
+	copied.ctx = ctx
+	return &copied
+}
+
+// Use adds interceptors to requests of this Space value only,
+// they run inside connection interceptors
+func (space *Space) Use(interceptors ...Interceptor) *Space {
+	space.interceptors = append(space.interceptors[:len(space.interceptors):len(space.interceptors)], interceptors...)
+	return space
+}
+
+func (space *Space) chain() Handler {
+	space.conn.mutex.RLock()
+	interceptors := append(space.conn.interceptors[:len(space.conn.interceptors):len(space.conn.interceptors)], space.interceptors...)
+	space.conn.mutex.RUnlock()
+
+	handler := space.conn.send
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(req *Request) ([][][]byte, error) {
+			return interceptor(req, next)
+		}
+	}
+	return handler
+}
+
+// Context is context of Space, see Space.WithContext
+func (req *Request) Context() context.Context {
+	if req.ctx == nil {
+		return context.Background()
+	}
+	return req.ctx
+}
+
+// WithContext returns a copy of request with ctx
+func (req *Request) WithContext(ctx context.Context) *Request {
+	copied := *req
+	copied.ctx = ctx
+	return &copied
+}
+
+// OpName is lower case name of request op
+func (req *Request) OpName() string {
+	switch req.Op {
+	case SelectOp:
+		return "select"
+	case InsertOp:
+		return "insert"
+	case UpdateOp:
+		return "update"
+	case DeleteOp:
+		return "delete"
+	case CallOp:
+		return "call"
+	case PingOp:
+		return "ping"
+	}
+	return fmt.Sprintf("op%d", req.Op)
+}
+
+// IndexNo is index of select, other requests use primary index
+func (req *Request) IndexNo() int32 {
+	if req.Op != SelectOp || len(req.Body) < 8 {
+		return 0
+	}
+	return int32(binary.LittleEndian.Uint32(req.Body[4:8]))
+}
+
+// ProcName is procedure name of call, empty for other requests
+func (req *Request) ProcName() string {
+	if req.Op != CallOp || len(req.Body) < 4 {
+		return ""
+	}
+	reader := bytes.NewReader(req.Body[4:])
+	size, err := binary.ReadUvarint(reader)
+	if err != nil || uint64(reader.Len()) < size {
+		return ""
+	}
+	offset := len(req.Body) - reader.Len()
+	return string(req.Body[offset : offset+int(size)])
+}
+
+// Keys decodes keys of select, key of update and delete,
+// tuple of insert and arguments of call
+func (req *Request) Keys() (keys [][][]byte, err error) {
+	reader := bytes.NewReader(req.Body)
+	var header []int32
+	count := int32(1)
+
+	switch req.Op {
+	case SelectOp:
+		// space, index, offset, limit
+		header = make([]int32, 4)
+	case InsertOp, UpdateOp, DeleteOp:
+		// space, flags
+		header = make([]int32, 2)
+	case CallOp:
+		header = make([]int32, 1)
+	default:
+		return
+	}
+	err = binary.Read(reader, binary.LittleEndian, header)
+	if err != nil {
+		return
+	}
+	if req.Op == CallOp {
+		var size uint64
+		size, err = binary.ReadUvarint(reader)
+		if err != nil {
+			return
+		}
+		_, err = reader.Seek(int64(size), 1)
+		if err != nil {
+			return
+		}
+	}
+	if req.Op == SelectOp {
+		err = binary.Read(reader, binary.LittleEndian, &count)
+		if err != nil {
+			return
+		}
+	}
+
+	keys = make([][][]byte, 0, count)
+	for i := int32(0); i < count; i++ {
+		var key [][]byte
+		key, err = readTuple(reader)
+		if err != nil {
+			return
+		}
+		keys = append(keys, key)
+	}
+	return
+}
+
+func readTuple(reader *bytes.Reader) (tuple [][]byte, err error) {
+	var cardinality int32
+	err = binary.Read(reader, binary.LittleEndian, &cardinality)
+	if err != nil {
+		return
+	}
+	if cardinality < 0 || int(cardinality) > reader.Len() {
+		err = fmt.Errorf("Wrong tuple cardinality %d", cardinality)
+		return
+	}
+	tuple = make([][]byte, cardinality)
+	for i := range tuple {
+		var size uint64
+		size, err = binary.ReadUvarint(reader)
+		if err != nil {
+			return
+		}
+		if size > uint64(reader.Len()) {
+			err = fmt.Errorf("Field of %d bytes is longer than request", size)
+			return
+		}
+		tuple[i] = make([]byte, size)
+		reader.Read(tuple[i])
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package tarantool
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	conn := &Connection{}
+	calls := []string{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		calls = append(calls, "conn "+req.OpName())
+		return next(req)
+	})
+	space := conn.Space(1).Use(func(req *Request, next Handler) ([][][]byte, error) {
+		calls = append(calls, "space")
+		return nil, errors.New("stop")
+	})
+
+	_, err := space.Select(2, 0, 10, []TupleField{String("a"), Int32(1)}, []TupleField{String("b")})
+	if err == nil || err.Error() != "stop" {
+		t.Errorf("Space interceptor should stop request, got %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "conn select" || calls[1] != "space" {
+		t.Errorf("Connection interceptor should run first, got %v", calls)
+	}
+}
+
+func TestRequestDecoding(t *testing.T) {
+	var last *Request
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		last = req
+		return nil, nil
+	})
+	space := conn.Space(1)
+
+	space.Select(2, 0, 10, []TupleField{String("a"), Int32(1)}, []TupleField{String("b")})
+	keys, err := last.Keys()
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if last.SpaceNo != 1 || last.IndexNo() != 2 || len(keys) != 2 || len(keys[0]) != 2 || string(keys[1][0]) != "b" {
+		t.Errorf("Wrong select decoded: index %d, keys %q", last.IndexNo(), keys)
+	}
+
+	space.Update([]TupleField{String("key")}, false, UpdOp{1, OpAdd, Int32(1)})
+	keys, _ = last.Keys()
+	if len(keys) != 1 || string(keys[0][0]) != "key" {
+		t.Errorf("Wrong update key %q", keys)
+	}
+
+	space.Call("box.dostring", true, String("return 1"))
+	keys, _ = last.Keys()
+	if last.ProcName() != "box.dostring" || len(keys) != 1 || string(keys[0][0]) != "return 1" {
+		t.Errorf("Wrong call %s decoded with args %q", last.ProcName(), keys)
+	}
+}
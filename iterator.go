@@ -0,0 +1,141 @@
+package tarantool
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type IteratorOptions struct {
+	IndexNo int32 // TREE index to walk
+	// Key fields of the index, {0} by default for the primary index,
+	// they are required for other indexes
+	KeyFields []int32
+	// Start key, iteration starts from the first tuple
+	// (the last one for Reverse) by default
+	Start []TupleField
+	// Tuples per request, 100 by default
+	BatchSize int32
+	// Reverse walks index backward with box.select_reverse_range
+	Reverse bool
+}
+
+// Iterator pages through TREE index with SelectRange
+// using the last seen key as a cursor for the next page.
+//
+//	iter := space.Iterator(tarantool.IteratorOptions{Start: []tarantool.TupleField{tarantool.Int32(2)}})
+//	for iter.Next() {
+//		fmt.Println(iter.Tuple())
+//	}
+//	if iter.Err() != nil {
+//		...
+//	}
+type Iterator struct {
+	space     *Space
+	opts      IteratorOptions
+	cursor    []TupleField
+	cursorKey [][]byte
+	// tuples with cursor key which are returned already,
+	// non-unique index may have more of them on the next page
+	seen  int32
+	page  [][][]byte
+	tuple [][]byte
+	done  bool
+	err   error
+}
+
+// Iterator fails with an error from Err when KeyFields are not given
+// for a secondary index
+func (space *Space) Iterator(opts IteratorOptions) *Iterator {
+	iter := &Iterator{space: space, opts: opts, cursor: opts.Start}
+	if opts.KeyFields == nil {
+		if opts.IndexNo != 0 {
+			iter.done, iter.err = true, fmt.Errorf("Key fields of index %d are unknown", opts.IndexNo)
+		}
+		iter.opts.KeyFields = []int32{0}
+	}
+	if opts.BatchSize <= 0 {
+		iter.opts.BatchSize = 100
+	}
+	return iter
+}
+
+// Next moves to the next tuple, it returns false when index is over
+// or on error
+func (iter *Iterator) Next() bool {
+	if len(iter.page) == 0 {
+		if iter.done {
+			iter.tuple = nil
+			return false
+		}
+		iter.page, iter.err = iter.fetch()
+		if iter.err != nil || len(iter.page) == 0 {
+			iter.done, iter.tuple = true, nil
+			return false
+		}
+	}
+	iter.tuple, iter.page = iter.page[0], iter.page[1:]
+	return true
+}
+
+func (iter *Iterator) Tuple() [][]byte {
+	return iter.tuple
+}
+
+func (iter *Iterator) Err() error {
+	return iter.err
+}
+
+func (iter *Iterator) fetch() (tuples [][][]byte, err error) {
+	var page [][][]byte
+	limit := iter.opts.BatchSize + iter.seen
+	if iter.opts.Reverse {
+		page, err = iter.space.SelectReverseRange(iter.opts.IndexNo, limit, iter.cursor...)
+	} else {
+		page, err = iter.space.SelectRange(iter.opts.IndexNo, limit, iter.cursor...)
+	}
+	if err != nil {
+		return
+	}
+
+	skip := int32(0)
+	for skip < iter.seen && int(skip) < len(page) && sameKey(tupleKey(page[skip], iter.opts.KeyFields), iter.cursorKey) {
+		skip++
+	}
+	// Short page means the end of index
+	iter.done = int32(len(page)) < limit
+	tuples = page[skip:]
+	if len(tuples) == 0 {
+		return
+	}
+
+	last := tupleKey(tuples[len(tuples)-1], iter.opts.KeyFields)
+	if !sameKey(last, iter.cursorKey) {
+		iter.cursor, iter.cursorKey, iter.seen = rawTuple(last), last, 0
+	}
+	for i := len(tuples) - 1; i >= 0 && sameKey(tupleKey(tuples[i], iter.opts.KeyFields), last); i-- {
+		iter.seen++
+	}
+	return
+}
+
+func tupleKey(tuple [][]byte, keyFields []int32) (key [][]byte) {
+	key = make([][]byte, len(keyFields))
+	for i, fieldNo := range keyFields {
+		if int(fieldNo) < len(tuple) {
+			key[i] = tuple[fieldNo]
+		}
+	}
+	return
+}
+
+func sameKey(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(0)
+
+	for i := 1; i <= 5; i++ {
+		space.Insert([]TupleField{Int32(i), String("Peter")}, false)
+	}
+	defer func() {
+		for i := 1; i <= 5; i++ {
+			space.Delete([]TupleField{Int32(i)}, false)
+		}
+	}()
+
+	iter := space.Iterator(IteratorOptions{Start: []TupleField{Int32(2)}, BatchSize: 2})
+	var ids []Int32
+	for iter.Next() {
+		var id Int32
+		id.Unpack(iter.Tuple()[0])
+		ids = append(ids, id)
+	}
+	if iter.Err() != nil {
+		t.Fatalf("Error: %s", iter.Err().Error())
+	}
+	if len(ids) != 4 || ids[0] != 2 || ids[3] != 5 {
+		t.Errorf("Ids from 2 to 5 should be returned, not %v", ids)
+	}
+
+	// Peter is repeated in non-unique index 1, paging must not lose or repeat it
+	iter = space.Iterator(IteratorOptions{IndexNo: 1, KeyFields: []int32{1}, BatchSize: 2, Reverse: true})
+	count := 0
+	for iter.Next() {
+		count++
+	}
+	if count != 5 {
+		t.Errorf("5 tuples should be returned backward, not %d", count)
+	}
+}
+
+func TestIteratorKeyFields(t *testing.T) {
+	iter := (&Connection{}).Space(0).Iterator(IteratorOptions{IndexNo: 1})
+	if iter.Next() {
+		t.Errorf("Iterator without key fields of secondary index should not return tuples")
+	}
+	if iter.Err() == nil {
+		t.Errorf("Error expected for unknown key fields of index 1")
+	}
+}
@@ -0,0 +1,262 @@
+package tarantool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrNotLocked = errors.New("Lock is not held")
+
+var lockProc = Builtins.Register("go_tarantool_lock", 1, `
+function go_tarantool_lock(space, name, owner, ttl)
+	space, ttl = tonumber(space), tonumber(ttl)
+	local now = math.floor(box.time() * 1000)
+	local tuple = box.select(space, 0, name)
+	if tuple == nil then
+		local ok, inserted = pcall(box.insert, space, name, owner, box.pack('l', 1), box.pack('l', now + ttl))
+		if not ok then
+			return 'locked'
+		end
+		return 'ok', inserted
+	end
+	local fence = box.unpack('l', tuple[2])
+	if tuple[1] ~= owner then
+		if box.unpack('l', tuple[3]) > now then
+			return 'locked', tuple
+		end
+		fence = fence + 1
+	end
+	return 'ok', box.update(space, name, '=p=p=p', 1, owner, 2, box.pack('l', fence), 3, box.pack('l', now + ttl))
+end`).Procedure("go_tarantool_lock")
+
+var lockRenewProc = Builtins.Register("go_tarantool_lock_renew", 1, `
+function go_tarantool_lock_renew(space, name, owner, fence, ttl)
+	space, ttl = tonumber(space), tonumber(ttl)
+	local tuple = box.select(space, 0, name)
+	if tuple == nil or tuple[1] ~= owner or tuple[2] ~= fence then
+		return 'lost'
+	end
+	local now = math.floor(box.time() * 1000)
+	box.update(space, name, '=p', 3, box.pack('l', now + ttl))
+	return 'ok'
+end`).Procedure("go_tarantool_lock_renew")
+
+// Lock is a lease in a dedicated space with tuples
+// {name STR, owner STR, fence NUM64, expires NUM64}
+// and primary HASH or TREE index on name.
+//
+// Every acquisition by a new owner increments fence token, pass it along
+// with writes to protected resources to reject writes from stale owners.
+// While lock is held its lease is renewed in background, Lost channel
+// is closed when renewal fails.
+type Lock struct {
+	space *Space
+	name  string
+	ttl   time.Duration
+	token string
+
+	mutex sync.Mutex
+	fence int64
+	held  bool
+	lost  chan struct{}
+	stop  chan struct{}
+}
+
+// Shorter ttl of locks is raised to minLockTTL
+const minLockTTL = 10 * time.Millisecond
+
+// Lock returns a lock with a new owner token, lease expires
+// after ttl unless renewed, ttl is at least 10ms
+func (space *Space) Lock(name string, ttl time.Duration) *Lock {
+	if ttl < minLockTTL {
+		ttl = minLockTTL
+	}
+	return &Lock{space: space, name: name, ttl: ttl, token: newToken()}
+}
+
+func (lock *Lock) Token() string {
+	return lock.token
+}
+
+// Fence returns fencing token of the current acquisition
+func (lock *Lock) Fence() int64 {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	return lock.fence
+}
+
+// Lost is closed when lock is lost or unlocked
+func (lock *Lock) Lost() <-chan struct{} {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	if lock.lost == nil {
+		lost := make(chan struct{})
+		close(lost)
+		return lost
+	}
+	return lock.lost
+}
+
+// TryLock acquires lock if it is free or expired
+func (lock *Lock) TryLock() (ok bool, err error) {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	if lock.held {
+		return true, nil
+	}
+
+	fence, ok, err := lock.acquire()
+	if err != nil || !ok {
+		return
+	}
+	lock.fence, lock.held = fence, true
+	lock.lost, lock.stop = make(chan struct{}), make(chan struct{})
+	go lock.renew(lock.lost, lock.stop)
+	return
+}
+
+// Lock waits for lock until ctx is done
+func (lock *Lock) Lock(ctx context.Context) (err error) {
+	retry := lock.ttl / 10
+	if retry < 10*time.Millisecond {
+		retry = 10 * time.Millisecond
+	}
+	if retry > time.Second {
+		retry = time.Second
+	}
+
+	for {
+		var ok bool
+		ok, err = lock.TryLock()
+		if err != nil || ok {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// Unlock expires the lease if lock is still owned. Tuple is updated
+// rather than deleted, so fence keeps growing with next acquisitions.
+func (lock *Lock) Unlock() (err error) {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	if !lock.held {
+		return ErrNotLocked
+	}
+	lock.release()
+
+	_, err = lock.space.CompareAndSet([]TupleField{String(lock.name)}, 1, String(lock.token), UpdOp{3, OpEq, Int64(0)})
+	if err == ErrConflict || err == ErrNotFound {
+		err = ErrNotLocked
+	}
+	return
+}
+
+// release stops renewals and signals Lost, mutex should be held
+func (lock *Lock) release() {
+	if !lock.held {
+		return
+	}
+	lock.held = false
+	close(lock.stop)
+	close(lock.lost)
+}
+
+// renew extends lease every third of ttl while owner and fence are
+// the same. Lock is lost when someone else takes it or when lease can't
+// be renewed before it expires. Mutex is held during renewal, so Unlock
+// waits for it and can't be overwritten by it.
+func (lock *Lock) renew(lost, stop chan struct{}) {
+	ticker := time.NewTicker(lock.ttl / 3)
+	defer ticker.Stop()
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		lock.mutex.Lock()
+		if !lock.held || lost != lock.lost {
+			// unlocked meanwhile
+			lock.mutex.Unlock()
+			return
+		}
+		ok, err := lock.extend()
+		switch {
+		case err == nil && ok:
+			renewed = time.Now()
+		case err != nil && time.Since(renewed) < lock.ttl:
+			// retry on the next tick while lease is still valid
+		default:
+			lock.release()
+			lock.mutex.Unlock()
+			return
+		}
+		lock.mutex.Unlock()
+	}
+}
+
+// extend renews lease of the current acquisition, mutex should be held
+func (lock *Lock) extend() (ok bool, err error) {
+	res, err := lockRenewProc.Call(lock.space,
+		String(strconv.Itoa(int(lock.space.spaceNo))),
+		String(lock.name),
+		String(lock.token),
+		Int64(lock.fence),
+		String(strconv.FormatInt(int64(lock.ttl/time.Millisecond), 10)))
+	if err != nil {
+		return
+	}
+	if len(res) != 1 || len(res[0]) != 1 {
+		err = fmt.Errorf("Unexpected lock result %v", res)
+		return
+	}
+	ok = string(res[0][0]) == "ok"
+	return
+}
+
+func (lock *Lock) acquire() (fence int64, ok bool, err error) {
+	res, err := lockProc.Call(lock.space,
+		String(strconv.Itoa(int(lock.space.spaceNo))),
+		String(lock.name),
+		String(lock.token),
+		String(strconv.FormatInt(int64(lock.ttl/time.Millisecond), 10)))
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0]) != 1 {
+		err = fmt.Errorf("Unexpected lock result %v", res)
+		return
+	}
+	if string(res[0][0]) != "ok" {
+		return
+	}
+	if len(res) != 2 || len(res[1]) < 3 {
+		err = fmt.Errorf("Unexpected lock result %v", res)
+		return
+	}
+
+	var val Int64
+	err = val.Unpack(res[1][2])
+	fence, ok = int64(val), err == nil
+	return
+}
+
+func newToken() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
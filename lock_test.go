@@ -0,0 +1,96 @@
+package tarantool
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(2)
+	defer space.Delete([]TupleField{String("test")}, false)
+
+	first := space.Lock("test", 300*time.Millisecond)
+	second := space.Lock("test", 300*time.Millisecond)
+
+	ok, err := first.TryLock()
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if !ok {
+		t.Fatalf("Free lock should be acquired")
+	}
+	fence := first.Fence()
+
+	ok, err = second.TryLock()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if ok {
+		t.Errorf("Held lock should not be acquired")
+	}
+
+	// Lease is renewed in background
+	time.Sleep(time.Second)
+	select {
+	case <-first.Lost():
+		t.Errorf("Lock should be renewed")
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go first.Unlock()
+	err = second.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if second.Fence() <= fence {
+		t.Errorf("Fence should grow from %d, got %d", fence, second.Fence())
+	}
+	<-first.Lost()
+
+	err = first.Unlock()
+	if err != ErrNotLocked {
+		t.Errorf("ErrNotLocked expected for released lock, not %v", err)
+	}
+	second.Unlock()
+}
+
+func TestLockRenewal(t *testing.T) {
+	renewals := make(chan [][]byte, 10)
+	conn := &Connection{}
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		args, _ := req.Keys()
+		switch req.ProcName() {
+		case "go_tarantool_lock":
+			return [][][]byte{{[]byte("ok")}, {[]byte("test"), args[0][2], {7, 0, 0, 0, 0, 0, 0, 0}, make([]byte, 8)}}, nil
+		case "go_tarantool_lock_renew":
+			renewals <- args[0]
+			return [][][]byte{{[]byte("lost")}}, nil
+		}
+		return nil, nil
+	})
+
+	// ttl is raised to the minimum
+	lock := conn.Space(2).Lock("test", 0)
+	ok, err := lock.TryLock()
+	if err != nil || !ok {
+		t.Fatalf("Lock should be acquired, got %v", err)
+	}
+
+	select {
+	case <-lock.Lost():
+	case <-time.After(time.Second):
+		t.Fatalf("Lock should be lost when lease can't be renewed")
+	}
+	args := <-renewals
+	if string(args[2]) != lock.Token() || !bytes.Equal(args[3], []byte{7, 0, 0, 0, 0, 0, 0, 0}) {
+		t.Errorf("Lease should be renewed for owner and fence, got %q", args)
+	}
+	if lock.Unlock() != ErrNotLocked {
+		t.Errorf("Lost lock should not be unlocked")
+	}
+}
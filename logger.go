@@ -0,0 +1,94 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type LogOptions struct {
+	// Requests longer than SlowThreshold are logged as slow,
+	// zero disables them
+	SlowThreshold time.Duration
+	// Keys of requests are logged only with LogKeys, fields are
+	// formatted by Redact
+	LogKeys bool
+	Redact  func(field []byte) string
+}
+
+// ConnectWithLogger is Connect logging dial and requests with logger,
+// see Connection.SetLogger
+func ConnectWithLogger(addr string, logger *slog.Logger, opts LogOptions) (conn *Connection, err error) {
+	start := time.Now()
+	conn, err = Connect(addr)
+	if err != nil {
+		logger.Error("tarantool dial failed", "addr", addr, "duration", time.Since(start), "error", err)
+		return
+	}
+	logger.Info("tarantool connected", "addr", addr, "duration", time.Since(start))
+	conn.SetLogger(logger, opts)
+	return
+}
+
+// SetLogger logs failed and slow requests. iproto doesn't tell about
+// disconnects, so network errors of requests are logged instead:
+// connection errors and malformed responses at error level, tarantool
+// errors at debug level.
+func (conn *Connection) SetLogger(logger *slog.Logger, opts LogOptions) {
+	if opts.Redact == nil {
+		opts.Redact = previewField
+	}
+
+	conn.Use(func(req *Request, next Handler) (tuples [][][]byte, err error) {
+		start := time.Now()
+		tuples, err = next(req)
+		duration := time.Since(start)
+
+		slow := opts.SlowThreshold > 0 && duration >= opts.SlowThreshold
+		if err == nil && !slow {
+			return
+		}
+
+		level, msg := slog.LevelWarn, "tarantool slow request"
+		var protocolErr *ProtocolError
+		switch {
+		case err == nil:
+		case ErrorCode(err) != 0:
+			level, msg = slog.LevelDebug, "tarantool error"
+		case errors.As(err, &protocolErr):
+			level, msg = slog.LevelError, "tarantool protocol error"
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			level, msg = slog.LevelDebug, "tarantool request canceled"
+		default:
+			level, msg = slog.LevelError, "tarantool connection error"
+		}
+
+		ctx := req.Context()
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("op", req.OpName()),
+			slog.Int("space", int(req.SpaceNo)),
+			slog.Duration("duration", duration),
+		}
+		if req.Op == SelectOp {
+			attrs = append(attrs, slog.Int("index", int(req.IndexNo())))
+		}
+		if req.Op == CallOp {
+			attrs = append(attrs, slog.String("proc", req.ProcName()))
+		}
+		if opts.LogKeys {
+			attrs = append(attrs, slog.String("key", keyPreview(req, opts.Redact, 0)))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+			if code := ErrorCode(err); code != 0 {
+				attrs = append(attrs, slog.Int("code", int(code)))
+			}
+		}
+		logger.LogAttrs(ctx, level, msg, attrs...)
+		return
+	})
+}
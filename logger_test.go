@@ -0,0 +1,66 @@
+package tarantool
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetLogger(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	conn := &Connection{}
+	conn.SetLogger(logger, LogOptions{SlowThreshold: 20 * time.Millisecond})
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		switch req.ProcName() {
+		case "broken":
+			return nil, &ProtocolError{io.ErrUnexpectedEOF}
+		case "missing":
+			return nil, &Error{CodeNoSuchProc, "Procedure 'missing' is not defined"}
+		case "slow":
+			time.Sleep(30 * time.Millisecond)
+		}
+		return nil, nil
+	})
+
+	space := conn.Space(1)
+	space.Call("fast", true)
+	space.Call("broken", true, String("secret"))
+	space.Call("missing", true)
+	space.Call("slow", true)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("3 events expected, got\n%s", buffer.String())
+	}
+	for i, expected := range []string{
+		`level=ERROR msg="tarantool protocol error" op=call space=1`,
+		`level=DEBUG msg="tarantool error" op=call space=1`,
+		`level=WARN msg="tarantool slow request" op=call space=1`,
+	} {
+		if !strings.Contains(lines[i], expected) {
+			t.Errorf("%s expected in %s", expected, lines[i])
+		}
+	}
+	if strings.Contains(buffer.String(), "secret") {
+		t.Errorf("Keys should not be logged by default")
+	}
+}
+
+func TestSetLoggerKeys(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	conn := &Connection{}
+	conn.SetLogger(slog.New(slog.NewTextHandler(buffer, nil)), LogOptions{LogKeys: true, Redact: RedactField})
+	conn.Use(func(req *Request, next Handler) ([][][]byte, error) {
+		return nil, &ProtocolError{io.ErrUnexpectedEOF}
+	})
+
+	conn.Space(1).Select(0, 0, 1, []TupleField{String("secret")})
+	if !strings.Contains(buffer.String(), `key="(<6 bytes>)"`) {
+		t.Errorf("Redacted key expected in %s", buffer.String())
+	}
+}
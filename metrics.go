@@ -0,0 +1,228 @@
+package tarantool
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Latency buckets in seconds
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Metrics collects counts, errors, traffic and latency of requests
+// per op and space. Add Interceptor to a connection, publish metrics
+// with expvar or serve them in Prometheus text format.
+type Metrics struct {
+	buckets []float64
+
+	mutex  sync.Mutex
+	series map[metricsKey]*MetricsSeries
+}
+
+type metricsKey struct {
+	op      string
+	spaceNo int32
+}
+
+// MetricsSeries are metrics of one op in one space. Errors are counted
+// by tarantool return code formatted as 0x3102, or "client" for network
+// and decoding errors. Received bytes are bytes of tuple fields.
+type MetricsSeries struct {
+	Op            string           `json:"op"`
+	SpaceNo       int32            `json:"space"`
+	Count         int64            `json:"count"`
+	Errors        map[string]int64 `json:"errors"`
+	BytesSent     int64            `json:"bytes_sent"`
+	BytesReceived int64            `json:"bytes_received"`
+	// Latency[i] counts requests not longer than Buckets[i],
+	// the last one counts all requests
+	Buckets    []float64 `json:"buckets"`
+	Latency    []int64   `json:"latency"`
+	LatencySum float64   `json:"latency_sum"`
+}
+
+// NewMetrics uses DefaultLatencyBuckets if buckets is nil
+func NewMetrics(buckets []float64) *Metrics {
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	return &Metrics{buckets: buckets, series: map[metricsKey]*MetricsSeries{}}
+}
+
+func (metrics *Metrics) Interceptor() Interceptor {
+	return func(req *Request, next Handler) (tuples [][][]byte, err error) {
+		start := time.Now()
+		tuples, err = next(req)
+		metrics.observe(req, tuples, err, time.Since(start))
+		return
+	}
+}
+
+func (metrics *Metrics) observe(req *Request, tuples [][][]byte, err error, duration time.Duration) {
+	received := 0
+	for _, tuple := range tuples {
+		for _, field := range tuple {
+			received += len(field)
+		}
+	}
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	key := metricsKey{req.OpName(), req.SpaceNo}
+	series, ok := metrics.series[key]
+	if !ok {
+		series = &MetricsSeries{
+			Op:      key.op,
+			SpaceNo: key.spaceNo,
+			Errors:  map[string]int64{},
+			Buckets: metrics.buckets,
+			Latency: make([]int64, len(metrics.buckets)+1),
+		}
+		metrics.series[key] = series
+	}
+
+	series.Count++
+	// 12 bytes of iproto header
+	series.BytesSent += int64(len(req.Body) + 12)
+	series.BytesReceived += int64(received)
+	if err != nil {
+		series.Errors[errorLabel(err)]++
+	}
+	seconds := duration.Seconds()
+	series.LatencySum += seconds
+	for i, bound := range metrics.buckets {
+		if seconds <= bound {
+			series.Latency[i]++
+		}
+	}
+	series.Latency[len(metrics.buckets)]++
+}
+
+// Snapshot returns copy of all series sorted by op and space
+func (metrics *Metrics) Snapshot() (snapshot []MetricsSeries) {
+	metrics.mutex.Lock()
+	for _, series := range metrics.series {
+		copied := *series
+		copied.Errors = map[string]int64{}
+		for code, count := range series.Errors {
+			copied.Errors[code] = count
+		}
+		copied.Latency = append([]int64(nil), series.Latency...)
+		snapshot = append(snapshot, copied)
+	}
+	metrics.mutex.Unlock()
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Op != snapshot[j].Op {
+			return snapshot[i].Op < snapshot[j].Op
+		}
+		return snapshot[i].SpaceNo < snapshot[j].SpaceNo
+	})
+	return
+}
+
+// String makes Metrics an expvar.Var
+func (metrics *Metrics) String() string {
+	snapshot := metrics.Snapshot()
+	if snapshot == nil {
+		snapshot = []MetricsSeries{}
+	}
+	data, _ := json.Marshal(snapshot)
+	return string(data)
+}
+
+// Publish exports metrics with expvar under name
+func (metrics *Metrics) Publish(name string) {
+	expvar.Publish(name, metrics)
+}
+
+// ServeHTTP writes metrics in Prometheus text format
+func (metrics *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.WritePrometheus(w)
+}
+
+func (metrics *Metrics) WritePrometheus(w io.Writer) (err error) {
+	snapshot := metrics.Snapshot()
+	counters := []struct {
+		name, help string
+		value      func(series *MetricsSeries) int64
+	}{
+		{"tarantool_requests_total", "Requests sent to tarantool.", func(series *MetricsSeries) int64 { return series.Count }},
+		{"tarantool_sent_bytes_total", "Bytes of requests.", func(series *MetricsSeries) int64 { return series.BytesSent }},
+		{"tarantool_received_bytes_total", "Bytes of tuples received.", func(series *MetricsSeries) int64 { return series.BytesReceived }},
+	}
+
+	for _, counter := range counters {
+		_, err = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", counter.name, counter.help, counter.name)
+		if err != nil {
+			return
+		}
+		for i := range snapshot {
+			_, err = fmt.Fprintf(w, "%s{%s} %d\n", counter.name, seriesLabels(&snapshot[i]), counter.value(&snapshot[i]))
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	_, err = fmt.Fprint(w, "# HELP tarantool_request_errors_total Failed requests by return code.\n# TYPE tarantool_request_errors_total counter\n")
+	if err != nil {
+		return
+	}
+	for i := range snapshot {
+		codes := []string{}
+		for code := range snapshot[i].Errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			_, err = fmt.Fprintf(w, "tarantool_request_errors_total{%s,code=%q} %d\n", seriesLabels(&snapshot[i]), code, snapshot[i].Errors[code])
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	_, err = fmt.Fprint(w, "# HELP tarantool_request_duration_seconds Request latency.\n# TYPE tarantool_request_duration_seconds histogram\n")
+	if err != nil {
+		return
+	}
+	for i := range snapshot {
+		series := &snapshot[i]
+		labels := seriesLabels(series)
+		for j, bound := range series.Buckets {
+			_, err = fmt.Fprintf(w, "tarantool_request_duration_seconds_bucket{%s,le=%q} %d\n", labels, strconv.FormatFloat(bound, 'g', -1, 64), series.Latency[j])
+			if err != nil {
+				return
+			}
+		}
+		_, err = fmt.Fprintf(w, "tarantool_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, series.Count)
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, "tarantool_request_duration_seconds_sum{%s} %g\ntarantool_request_duration_seconds_count{%s} %d\n", labels, series.LatencySum, labels, series.Count)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func seriesLabels(series *MetricsSeries) string {
+	return fmt.Sprintf("op=%q,space=\"%d\"", series.Op, series.SpaceNo)
+}
+
+func errorLabel(err error) string {
+	code := ErrorCode(err)
+	if code == 0 {
+		return "client"
+	}
+	return fmt.Sprintf("0x%x", code)
+}
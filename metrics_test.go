@@ -0,0 +1,56 @@
+package tarantool
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics([]float64{1})
+	conn := &Connection{}
+	conn.Use(metrics.Interceptor(), func(req *Request, next Handler) ([][][]byte, error) {
+		if req.Op == InsertOp {
+			return nil, &Error{CodeTupleFound, "Duplicate key exists"}
+		}
+		return [][][]byte{{[]byte("abc"), []byte("de")}}, nil
+	})
+
+	conn.Space(1).Select(0, 0, 1, []TupleField{String("abc")})
+	conn.Space(1).Select(0, 0, 1, []TupleField{String("de")})
+	conn.Space(2).Add([]TupleField{String("abc")}, false)
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("2 series expected, not %d", len(snapshot))
+	}
+	if snapshot[1].Op != "select" || snapshot[1].Count != 2 || snapshot[1].BytesReceived != 10 {
+		t.Errorf("Wrong select series %+v", snapshot[1])
+	}
+	if snapshot[0].Errors["0x3702"] != 1 {
+		t.Errorf("Insert error should be counted by code, got %v", snapshot[0].Errors)
+	}
+
+	var decoded []MetricsSeries
+	err := json.Unmarshal([]byte(metrics.String()), &decoded)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+
+	buffer := new(bytes.Buffer)
+	err = metrics.WritePrometheus(buffer)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	for _, line := range []string{
+		`tarantool_requests_total{op="select",space="1"} 2`,
+		`tarantool_request_errors_total{op="insert",space="2",code="0x3702"} 1`,
+		`tarantool_request_duration_seconds_bucket{op="select",space="1",le="1"} 2`,
+		`tarantool_request_duration_seconds_count{op="insert",space="2"} 1`,
+	} {
+		if !strings.Contains(buffer.String(), line+"\n") {
+			t.Errorf("%s expected in\n%s", line, buffer.String())
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Task statuses of tarantool queue
+const (
+	TaskReady   = "r"
+	TaskTaken   = "t"
+	TaskDelayed = "d"
+	TaskBuried  = "b"
+	TaskDone    = "D"
+)
+
+// Queue is a client of tarantool queue lua module,
+// https://github.com/tarantool/queue, keeping tasks in a space
+type Queue struct {
+	space *Space
+}
+
+type Tube struct {
+	queue *Queue
+	name  string
+}
+
+type PutOptions struct {
+	Delay    time.Duration // task is ready after delay
+	TTL      time.Duration // task is deleted after ttl
+	TTR      time.Duration // taken task is released after ttr
+	Priority int
+}
+
+// Task comes as tuple {id, tube, status, data...}
+type Task struct {
+	Id     string
+	Tube   string
+	Status string
+	Data   [][]byte
+	queue  *Queue
+}
+
+// Queue returns queue keeping tasks in space spaceNo
+func (conn *Connection) Queue(spaceNo int32) *Queue {
+	return &Queue{conn.Space(spaceNo)}
+}
+
+func (queue *Queue) Tube(name string) *Tube {
+	return &Tube{queue, name}
+}
+
+// Put adds task with data fields
+func (tube *Tube) Put(opts PutOptions, data ...TupleField) (task *Task, err error) {
+	args := []TupleField{
+		tube.queue.spaceNo(),
+		String(tube.name),
+		seconds(opts.Delay),
+		seconds(opts.TTL),
+		seconds(opts.TTR),
+		String(strconv.Itoa(opts.Priority)),
+	}
+	task, err = tube.queue.call("queue.put", append(args, data...)...)
+	return
+}
+
+// Take waits up to timeout for a ready task, nil task means timeout
+func (tube *Tube) Take(timeout time.Duration) (task *Task, err error) {
+	task, err = tube.queue.call("queue.take", tube.queue.spaceNo(), String(tube.name), seconds(timeout))
+	return
+}
+
+// Kick moves up to count buried tasks back to ready
+func (tube *Tube) Kick(count int) (kicked int, err error) {
+	res, err := tube.queue.space.Call("queue.kick", true, tube.queue.spaceNo(), String(tube.name), String(strconv.Itoa(count)))
+	if err != nil || len(res) == 0 || len(res[0]) == 0 {
+		return
+	}
+	n, err := UnpackNumber(res[0][0])
+	kicked = int(n)
+	return
+}
+
+// Consume takes tasks and runs handler for them with up to concurrency
+// tasks at once until ctx is done. Task is acked when handler succeeds
+// and released otherwise.
+func (tube *Tube) Consume(ctx context.Context, concurrency int, handler func(*Task) error) (err error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var handlers sync.WaitGroup
+	defer handlers.Wait()
+	slots := make(chan struct{}, concurrency)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		var task *Task
+		task, err = tube.Take(time.Second)
+		if err != nil {
+			return
+		}
+		if task == nil {
+			<-slots
+			continue
+		}
+
+		handlers.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				handlers.Done()
+			}()
+			if handler(task) != nil {
+				task.Release(0)
+			} else {
+				task.Ack()
+			}
+		}()
+	}
+}
+
+// Peek returns task by id without taking it
+func (queue *Queue) Peek(id string) (task *Task, err error) {
+	task, err = queue.call("queue.peek", queue.spaceNo(), String(id))
+	return
+}
+
+// Statistics returns counters of all tubes keyed by names like
+// "tube.name.tasks.ready"
+func (queue *Queue) Statistics() (stats map[string]string, err error) {
+	res, err := queue.space.Call("queue.statistics", true)
+	if err != nil {
+		return
+	}
+	stats = map[string]string{}
+	for _, tuple := range res {
+		for i := 0; i+1 < len(tuple); i += 2 {
+			stats[string(tuple[i])] = string(tuple[i+1])
+		}
+	}
+	return
+}
+
+// Ack marks taken task as done
+func (task *Task) Ack() (err error) {
+	err = task.update("queue.ack", String(task.Id))
+	return
+}
+
+// Release returns taken task to ready after delay
+func (task *Task) Release(delay time.Duration) (err error) {
+	err = task.update("queue.release", String(task.Id), seconds(delay))
+	return
+}
+
+// Bury disables task until it is kicked
+func (task *Task) Bury() (err error) {
+	err = task.update("queue.bury", String(task.Id))
+	return
+}
+
+func (task *Task) Delete() (err error) {
+	err = task.update("queue.delete", String(task.Id))
+	return
+}
+
+func (task *Task) update(procName string, args ...TupleField) (err error) {
+	updated, err := task.queue.call(procName, append([]TupleField{task.queue.spaceNo()}, args...)...)
+	if err == nil && updated != nil {
+		task.Status = updated.Status
+	}
+	return
+}
+
+func (queue *Queue) call(procName string, args ...TupleField) (task *Task, err error) {
+	res, err := queue.space.Call(procName, true, args...)
+	if err != nil || len(res) == 0 {
+		return
+	}
+	tuple := res[0]
+	if len(tuple) < 3 {
+		err = fmt.Errorf("Unexpected %s result %v", procName, res)
+		return
+	}
+	task = &Task{string(tuple[0]), string(tuple[1]), string(tuple[2]), tuple[3:], queue}
+	return
+}
+
+func (queue *Queue) spaceNo() TupleField {
+	return String(strconv.Itoa(int(queue.space.spaceNo)))
+}
+
+func seconds(duration time.Duration) TupleField {
+	return String(strconv.FormatFloat(duration.Seconds(), 'f', -1, 64))
+}
@@ -0,0 +1,70 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	loaded, err := conn.Space(10).DoString("return type(queue)")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if len(loaded) != 1 || len(loaded[0]) != 1 || string(loaded[0][0]) != "table" {
+		t.Skip("queue module is not loaded")
+	}
+	tube := conn.Queue(10).Tube("test")
+
+	task, err := tube.Put(PutOptions{TTL: time.Minute}, String("hello"))
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if task.Status != TaskReady || string(task.Data[0]) != "hello" {
+		t.Errorf("Ready task with hello expected, got %+v", task)
+	}
+
+	taken, err := tube.Take(time.Second)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if taken == nil || taken.Id != task.Id {
+		t.Fatalf("Task %q should be taken, got %+v", task.Id, taken)
+	}
+	err = taken.Bury()
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	kicked, err := tube.Kick(1)
+	if err != nil || kicked != 1 {
+		t.Errorf("1 task should be kicked, not %d, %v", kicked, err)
+	}
+
+	// Failed task is released and handled again
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	err = tube.Consume(ctx, 2, func(task *Task) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("try again")
+		}
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if calls != 2 {
+		t.Errorf("Task should be handled twice, not %d times", calls)
+	}
+
+	peeked, err := conn.Queue(10).Peek(task.Id)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if peeked != nil && peeked.Status != TaskDone {
+		t.Errorf("Task should be done, not %q", peeked.Status)
+	}
+}
@@ -0,0 +1,129 @@
+package tarantool
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type RateLimitMode int
+
+const (
+	// TokenBucket allows bursts up to limit, tokens are refilled
+	// evenly so that the full bucket takes window
+	TokenBucket RateLimitMode = iota
+	// SlidingWindow allows limit per window, the previous fixed window
+	// is weighted by its overlap with the sliding one
+	SlidingWindow
+)
+
+var rateLimitModes = map[RateLimitMode]string{
+	TokenBucket:   "bucket",
+	SlidingWindow: "window",
+}
+
+var rateLimitProc = Builtins.Register("go_tarantool_ratelimit", 1, `
+local function bucket(space, key, limit, window, cost, now)
+	local tokens, updated = limit, now
+	local tuple = box.select(space, 0, key)
+	if tuple ~= nil then
+		tokens, updated = tonumber(tuple[1]), tonumber(tuple[2])
+		tokens = math.min(limit, tokens + (now - updated) * limit / window)
+	end
+	local allowed = 0
+	if tokens >= cost then
+		tokens = tokens - cost
+		allowed = 1
+	end
+	box.replace(space, key, tostring(tokens), tostring(now))
+	return allowed, math.floor(tokens), math.ceil((limit - tokens) * window / limit)
+end
+
+local function sliding(space, key, limit, window, cost, now)
+	local start = now - now % window
+	local prev, curr = 0, 0
+	local tuple = box.select(space, 0, key)
+	if tuple ~= nil then
+		local last = tonumber(tuple[3])
+		if last == start then
+			prev, curr = tonumber(tuple[1]), tonumber(tuple[2])
+		elseif last == start - window then
+			prev = tonumber(tuple[2])
+		end
+	end
+	local used = prev * (window - (now - start)) / window + curr
+	local allowed = 0
+	if used + cost <= limit then
+		curr, used = curr + cost, used + cost
+		allowed = 1
+	end
+	box.replace(space, key, tostring(prev), tostring(curr), tostring(start))
+	return allowed, math.max(0, math.floor(limit - used)), start + window - now
+end
+
+function go_tarantool_ratelimit(space, mode, key, limit, window, cost)
+	space, limit, window, cost = tonumber(space), tonumber(limit), tonumber(window), tonumber(cost)
+	local now = math.floor(box.time() * 1000)
+	local check = sliding
+	if mode == 'bucket' then
+		check = bucket
+	end
+	local allowed, remaining, reset = check(space, key, limit, window, cost, now)
+	return {tostring(allowed), tostring(remaining), tostring(reset)}
+end`).Procedure("go_tarantool_ratelimit")
+
+// RateLimiter keeps limits state in a space with primary index on key STR,
+// so many app servers share the same limits. Check and consume is atomic.
+// Limiters of different modes should not share keys.
+type RateLimiter struct {
+	space  *Space
+	mode   RateLimitMode
+	limit  int
+	window time.Duration
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// ResetAfter is the time till the full limit is available again
+	// for TokenBucket and till the current window ends for SlidingWindow
+	ResetAfter time.Duration
+}
+
+// RateLimiter allows limit requests per window for every key
+func (space *Space) RateLimiter(mode RateLimitMode, limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{space, mode, limit, window}
+}
+
+func (limiter *RateLimiter) Allow(key string) (result RateLimitResult, err error) {
+	result, err = limiter.AllowN(key, 1)
+	return
+}
+
+// AllowN consumes cost requests at once if they fit the limit
+func (limiter *RateLimiter) AllowN(key string, cost int) (result RateLimitResult, err error) {
+	res, err := rateLimitProc.Call(limiter.space,
+		String(strconv.Itoa(int(limiter.space.spaceNo))),
+		String(rateLimitModes[limiter.mode]),
+		String(key),
+		String(strconv.Itoa(limiter.limit)),
+		String(strconv.FormatInt(int64(limiter.window/time.Millisecond), 10)),
+		String(strconv.Itoa(cost)))
+	if err != nil {
+		return
+	}
+	if len(res) != 1 || len(res[0]) != 3 {
+		err = fmt.Errorf("Unexpected rate limit result %v", res)
+		return
+	}
+
+	numbers := make([]int64, 3)
+	for i, field := range res[0] {
+		numbers[i], err = strconv.ParseInt(string(field), 10, 64)
+		if err != nil {
+			return
+		}
+	}
+	result = RateLimitResult{numbers[0] == 1, int(numbers[1]), time.Duration(numbers[2]) * time.Millisecond}
+	return
+}
@@ -0,0 +1,36 @@
+package tarantool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	// Any space with STR primary key fits
+	space := conn.Space(2)
+
+	for _, mode := range []RateLimitMode{TokenBucket, SlidingWindow} {
+		key := "test:" + rateLimitModes[mode]
+		defer space.Delete([]TupleField{String(key)}, false)
+		limiter := space.RateLimiter(mode, 3, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			result, err := limiter.Allow(key)
+			if err != nil {
+				t.Fatalf("Error: %s", err.Error())
+			}
+			if !result.Allowed || result.Remaining != 2-i {
+				t.Errorf("Request %d should be allowed with %d remaining, got %+v", i, 2-i, result)
+			}
+		}
+
+		result, err := limiter.Allow(key)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if result.Allowed || result.ResetAfter <= 0 {
+			t.Errorf("Request over limit should be denied with reset time, got %+v", result)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job is kept as tuple {id STR, run_at NUM64, name STR, payload STR,
+// attempts NUM, interval NUM64}, times are unix milliseconds
+type Job struct {
+	Id       string
+	RunAt    time.Time
+	Name     string
+	Payload  []byte
+	Attempts int
+	// Interval is zero for one time jobs
+	Interval time.Duration
+}
+
+type JobHandler func(job *Job) error
+
+type SchedulerOptions struct {
+	// TREE index on (run_at, id), 1 by default
+	RunIndex int32
+	// Jobs failed MaxAttempts times are moved to DeadSpace with error
+	// message appended, or deleted when it is nil
+	DeadSpace   *Space
+	MaxAttempts int
+	// Backoff returns delay before the next attempt
+	Backoff func(attempts int) time.Duration
+	// Claimed job is hidden for Lease, it runs again if lease expires
+	// before job is done
+	Lease        time.Duration
+	PollInterval time.Duration
+	Concurrency  int
+	BatchSize    int32
+}
+
+// Scheduler runs delayed and recurring jobs stored in a space.
+// Due jobs are found by a range scan over run_at index and claimed
+// with compare and set on run_at, so many schedulers may share a space.
+type Scheduler struct {
+	space    *Space
+	opts     SchedulerOptions
+	mutex    sync.Mutex
+	handlers map[string]JobHandler
+}
+
+func (space *Space) Scheduler(opts SchedulerOptions) *Scheduler {
+	if opts.RunIndex == 0 {
+		opts.RunIndex = 1
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = exponentialBackoff
+	}
+	if opts.Lease == 0 {
+		opts.Lease = time.Minute
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = 1
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 100
+	}
+	return &Scheduler{space: space, opts: opts, handlers: map[string]JobHandler{}}
+}
+
+// Handle registers handler for jobs with name, scheduler runs only jobs
+// it has handlers for
+func (scheduler *Scheduler) Handle(name string, handler JobHandler) {
+	scheduler.mutex.Lock()
+	scheduler.handlers[name] = handler
+	scheduler.mutex.Unlock()
+}
+
+// Schedule adds job to run once at runAt
+func (scheduler *Scheduler) Schedule(name string, payload []byte, runAt time.Time) (id string, err error) {
+	id, err = scheduler.add(name, payload, runAt, 0)
+	return
+}
+
+// Every adds job to run every interval starting after the first interval
+func (scheduler *Scheduler) Every(name string, payload []byte, interval time.Duration) (id string, err error) {
+	id, err = scheduler.add(name, payload, time.Now().Add(interval), interval)
+	return
+}
+
+func (scheduler *Scheduler) Cancel(id string) (err error) {
+	_, err = scheduler.space.Delete([]TupleField{String(id)}, false)
+	return
+}
+
+func (scheduler *Scheduler) add(name string, payload []byte, runAt time.Time, interval time.Duration) (id string, err error) {
+	id = newToken()
+	_, err = scheduler.space.Add([]TupleField{
+		String(id),
+		Int64(unixMillis(runAt)),
+		String(name),
+		String(payload),
+		Int32(0),
+		Int64(int64(interval / time.Millisecond)),
+	}, false)
+	return
+}
+
+// Run polls due jobs and runs them until ctx is done,
+// running jobs are waited for
+func (scheduler *Scheduler) Run(ctx context.Context) (err error) {
+	var jobs sync.WaitGroup
+	defer jobs.Wait()
+	slots := make(chan struct{}, scheduler.opts.Concurrency)
+	ticker := time.NewTicker(scheduler.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		err = scheduler.poll(ctx, slots, &jobs)
+		if err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler *Scheduler) poll(ctx context.Context, slots chan struct{}, jobs *sync.WaitGroup) (err error) {
+	now := time.Now()
+	iter := scheduler.space.Iterator(IteratorOptions{
+		IndexNo:   scheduler.opts.RunIndex,
+		KeyFields: []int32{1, 0},
+		BatchSize: scheduler.opts.BatchSize,
+	})
+	for iter.Next() {
+		var job *Job
+		job, err = decodeJob(iter.Tuple())
+		if err != nil {
+			return
+		}
+		if job.RunAt.After(now) {
+			break
+		}
+		handler := scheduler.handler(job.Name)
+		if handler == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+		var claimed bool
+		claimed, err = scheduler.claim(job)
+		if err != nil || !claimed {
+			<-slots
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		jobs.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				jobs.Done()
+			}()
+			scheduler.finish(job, handler(job))
+		}()
+	}
+	err = iter.Err()
+	return
+}
+
+// claim moves run_at to the end of lease if nobody has claimed job yet
+func (scheduler *Scheduler) claim(job *Job) (claimed bool, err error) {
+	leaseEnd := time.Now().Add(scheduler.opts.Lease)
+	_, err = scheduler.space.CompareAndSet([]TupleField{String(job.Id)}, 1, Int64(unixMillis(job.RunAt)),
+		UpdOp{1, OpEq, Int64(unixMillis(leaseEnd))},
+		UpdOp{4, OpAdd, Int32(1)})
+	if err == ErrConflict || err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return
+	}
+	job.RunAt = leaseEnd
+	job.Attempts++
+	return true, nil
+}
+
+// finish reschedules or deletes job while lease is still ours.
+// Errors are dropped, job runs again when its lease expires.
+func (scheduler *Scheduler) finish(job *Job, jobErr error) {
+	key := []TupleField{String(job.Id)}
+	leaseEnd := Int64(unixMillis(job.RunAt))
+
+	switch {
+	case jobErr == nil && job.Interval > 0:
+		next := time.Now().Add(job.Interval)
+		scheduler.space.CompareAndSet(key, 1, leaseEnd, UpdOp{1, OpEq, Int64(unixMillis(next))}, UpdOp{4, OpEq, Int32(0)})
+	case jobErr == nil:
+		scheduler.space.Delete(key, false)
+	case job.Attempts < scheduler.opts.MaxAttempts:
+		next := time.Now().Add(scheduler.opts.Backoff(job.Attempts))
+		scheduler.space.CompareAndSet(key, 1, leaseEnd, UpdOp{1, OpEq, Int64(unixMillis(next))})
+	default:
+		if scheduler.opts.DeadSpace != nil {
+			_, err := scheduler.opts.DeadSpace.Insert(append(job.tuple(), String(jobErr.Error())), false)
+			if err != nil {
+				return
+			}
+		}
+		scheduler.space.Delete(key, false)
+	}
+}
+
+func (scheduler *Scheduler) handler(name string) JobHandler {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.handlers[name]
+}
+
+func (job *Job) tuple() []TupleField {
+	return []TupleField{
+		String(job.Id),
+		Int64(unixMillis(job.RunAt)),
+		String(job.Name),
+		String(job.Payload),
+		Int32(job.Attempts),
+		Int64(int64(job.Interval / time.Millisecond)),
+	}
+}
+
+func decodeJob(tuple [][]byte) (job *Job, err error) {
+	if len(tuple) < 6 {
+		err = fmt.Errorf("Job should have 6 fields, not %d", len(tuple))
+		return
+	}
+	var (
+		runAt, interval Int64
+		attempts        Int32
+	)
+	for _, unpack := range []error{runAt.Unpack(tuple[1]), attempts.Unpack(tuple[4]), interval.Unpack(tuple[5])} {
+		if unpack != nil {
+			err = unpack
+			return
+		}
+	}
+	job = &Job{
+		Id:       string(tuple[0]),
+		RunAt:    time.Unix(0, int64(runAt)*int64(time.Millisecond)),
+		Name:     string(tuple[2]),
+		Payload:  tuple[3],
+		Attempts: int(attempts),
+		Interval: time.Duration(interval) * time.Millisecond,
+	}
+	return
+}
+
+// exponentialBackoff waits 1s, 2s, 4s... up to an hour
+func exponentialBackoff(attempts int) time.Duration {
+	if attempts > 12 {
+		return time.Hour
+	}
+	return time.Second << uint(attempts-1)
+}
@@ -0,0 +1,80 @@
+package tarantool
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	defer conn.Space(4).Truncate()
+	defer conn.Space(5).Truncate()
+
+	scheduler := conn.Space(4).Scheduler(SchedulerOptions{
+		DeadSpace:    conn.Space(5),
+		MaxAttempts:  2,
+		Backoff:      func(int) time.Duration { return 10 * time.Millisecond },
+		PollInterval: 10 * time.Millisecond,
+		Concurrency:  2,
+	})
+
+	done := make(chan string, 10)
+	scheduler.Handle("ok", func(job *Job) error {
+		done <- string(job.Payload)
+		return nil
+	})
+	scheduler.Handle("fail", func(job *Job) error {
+		return errors.New("broken")
+	})
+
+	_, err := scheduler.Schedule("ok", []byte("hello"), time.Now())
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	failId, err := scheduler.Schedule("fail", nil, time.Now())
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	laterId, _ := scheduler.Schedule("ok", []byte("later"), time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err = scheduler.Run(ctx)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+
+	if len(done) != 1 || <-done != "hello" {
+		t.Errorf("Only due job should run")
+	}
+	tuples, _ := conn.Space(5).Select(0, 0, 1, []TupleField{String(failId)})
+	if len(tuples) != 1 || string(tuples[0][6]) != "broken" {
+		t.Errorf("Failed job should be moved to dead letters, got %q", tuples)
+	}
+	err = scheduler.Cancel(laterId)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if n, _ := conn.Space(4).Len(); n != 0 {
+		t.Errorf("No jobs should be left, not %d", n)
+	}
+}
+
+func TestDecodeJob(t *testing.T) {
+	job := &Job{"id", time.Unix(1000, 0), "name", []byte("payload"), 3, time.Minute}
+	runAt, attempts, interval := make([]byte, 8), make([]byte, 4), make([]byte, 8)
+	binary.LittleEndian.PutUint64(runAt, 1000000)
+	binary.LittleEndian.PutUint32(attempts, 3)
+	binary.LittleEndian.PutUint64(interval, 60000)
+	tuple := [][]byte{[]byte("id"), runAt, []byte("name"), []byte("payload"), attempts, interval}
+	decoded, err := decodeJob(tuple)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if !decoded.RunAt.Equal(job.RunAt) || decoded.Attempts != 3 || decoded.Interval != time.Minute || string(decoded.Payload) != "payload" {
+		t.Errorf("Job %v decoded as %v", job, decoded)
+	}
+}
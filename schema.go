@@ -0,0 +1,176 @@
+package tarantool
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Key field types as they are declared in tarantool.cfg
+const (
+	NumType   = "NUM"
+	Num64Type = "NUM64"
+	StrType   = "STR"
+)
+
+// Schema describes spaces and indexes declared in tarantool.cfg.
+// Tarantool only knows types of indexed fields, so do we.
+type Schema struct {
+	Spaces map[int32]*SpaceSchema
+}
+
+type SpaceSchema struct {
+	Enabled bool
+	Indexes map[int32]*IndexSchema
+}
+
+type IndexSchema struct {
+	Type      string // TREE or HASH
+	Unique    bool
+	KeyFields []KeyField
+}
+
+type KeyField struct {
+	FieldNo int32
+	Type    string
+}
+
+var (
+	configLine  = regexp.MustCompile(`^space\[(\d+)\]\.(.+?)\s*=\s*(.+)$`)
+	indexOption = regexp.MustCompile(`^index\[(\d+)\]\.(.+)$`)
+	keyOption   = regexp.MustCompile(`^key_field\[(\d+)\]\.(fieldno|type)$`)
+)
+
+// LoadConfig reads schema from tarantool.cfg file
+func LoadConfig(path string) (schema *Schema, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	schema, err = ParseConfig(file)
+	return
+}
+
+// ParseConfig reads space definitions from tarantool.cfg.
+// All other options are skipped.
+func ParseConfig(reader io.Reader) (schema *Schema, err error) {
+	schema = &Schema{map[int32]*SpaceSchema{}}
+
+	scanner := bufio.NewScanner(reader)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		match := configLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		err = schema.set(match[1], match[2], strings.Trim(match[3], `"`))
+		if err != nil {
+			err = fmt.Errorf("Config line %d: %s", lineNo, err.Error())
+			return
+		}
+	}
+	err = scanner.Err()
+	return
+}
+
+func (schema *Schema) set(spaceNo, option, value string) (err error) {
+	space := schema.spaceAt(atoi32(spaceNo))
+
+	if option == "enabled" {
+		space.Enabled = value == "1"
+		return
+	}
+
+	match := indexOption.FindStringSubmatch(option)
+	if match == nil {
+		return
+	}
+	index := space.indexAt(atoi32(match[1]))
+
+	switch match[2] {
+	case "type":
+		index.Type = value
+		return
+	case "unique":
+		index.Unique = value == "1"
+		return
+	}
+
+	match = keyOption.FindStringSubmatch(match[2])
+	if match == nil {
+		return
+	}
+	keyField := index.keyFieldAt(int(atoi32(match[1])))
+
+	if match[2] == "type" {
+		keyField.Type = value
+		return
+	}
+	fieldNo, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return
+	}
+	keyField.FieldNo = int32(fieldNo)
+	return
+}
+
+func (schema *Schema) spaceAt(spaceNo int32) (space *SpaceSchema) {
+	space, ok := schema.Spaces[spaceNo]
+	if !ok {
+		space = &SpaceSchema{false, map[int32]*IndexSchema{}}
+		schema.Spaces[spaceNo] = space
+	}
+	return
+}
+
+func (space *SpaceSchema) indexAt(indexNo int32) (index *IndexSchema) {
+	index, ok := space.Indexes[indexNo]
+	if !ok {
+		index = new(IndexSchema)
+		space.Indexes[indexNo] = index
+	}
+	return
+}
+
+func (index *IndexSchema) keyFieldAt(i int) *KeyField {
+	for len(index.KeyFields) <= i {
+		index.KeyFields = append(index.KeyFields, KeyField{})
+	}
+	return &index.KeyFields[i]
+}
+
+// Space returns nil for spaces not mentioned in config
+func (schema *Schema) Space(spaceNo int32) *SpaceSchema {
+	return schema.Spaces[spaceNo]
+}
+
+// Index returns nil for indexes not mentioned in config
+func (space *SpaceSchema) Index(indexNo int32) *IndexSchema {
+	return space.Indexes[indexNo]
+}
+
+// FieldType returns type of a field if it is a part of any index,
+// empty string otherwise
+func (space *SpaceSchema) FieldType(fieldNo int32) string {
+	for _, index := range space.Indexes {
+		for _, keyField := range index.KeyFields {
+			if keyField.FieldNo == fieldNo {
+				return keyField.Type
+			}
+		}
+	}
+	return ""
+}
+
+func atoi32(s string) int32 {
+	i, _ := strconv.ParseInt(s, 10, 32)
+	return int32(i)
+}
@@ -0,0 +1,42 @@
+package tarantool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	schema, err := LoadConfig("tarantool.cfg")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+
+	if len(schema.Spaces) != 15 {
+		t.Errorf("15 spaces should be loaded not %d", len(schema.Spaces))
+	}
+
+	index := schema.Space(1).Index(0)
+	if index.Type != "TREE" || !index.Unique {
+		t.Errorf("Space 1 primary index should be unique TREE, not %v", index)
+	}
+	if len(index.KeyFields) != 2 {
+		t.Fatalf("Space 1 primary index should have 2 key fields not %d", len(index.KeyFields))
+	}
+	if index.KeyFields[1] != (KeyField{1, NumType}) {
+		t.Errorf("Second key field should be NUM field 1, not %v", index.KeyFields[1])
+	}
+
+	if fieldType := schema.Space(0).FieldType(1); fieldType != StrType {
+		t.Errorf("Field 1 of space 0 should be STR not %q", fieldType)
+	}
+	if fieldType := schema.Space(0).FieldType(2); fieldType != "" {
+		t.Errorf("Field 2 of space 0 is not indexed, got %q", fieldType)
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	_, err := ParseConfig(strings.NewReader("space[0].index[0].key_field[0].fieldno = zero\n"))
+	if err == nil {
+		t.Errorf("Error expected for invalid fieldno")
+	}
+}
@@ -0,0 +1,132 @@
+package tarantool
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Installed script versions are kept in this lua global
+const scriptVersions = "__go_tarantool_scripts"
+
+var (
+	scriptVersionCode = "local name = ... " +
+		"local versions = rawget(_G, '" + scriptVersions + "') " +
+		"return tostring(versions and versions[name] or '')"
+	scriptMarkCode = "local name, version = ... " +
+		"if rawget(_G, '" + scriptVersions + "') == nil then rawset(_G, '" + scriptVersions + "', {}) end " +
+		scriptVersions + "[name] = version"
+)
+
+// Script is a named lua chunk defining stored procedures
+type Script struct {
+	Name    string
+	Version int
+	Source  string
+	mutex   sync.Mutex
+}
+
+// Procedure is a lua function defined by a script,
+// typed Go stubs wrap its Call:
+//
+//	var greet = scripts.Register("greet", 1, `function greet(name) return 'Hello, ' .. name end`).Procedure("greet")
+//
+//	func Greet(space *tarantool.Space, name string) (greeting string, err error) {
+//		res, err := greet.Call(space, tarantool.String(name))
+//		...
+//	}
+type Procedure struct {
+	Name   string
+	script *Script
+}
+
+// ScriptManager holds lua scripts in Go and installs them with box.dostring.
+// Installed version is marked on server, so scripts are reinstalled
+// only when version changes. Install them on connect, procedures
+// reinstall their script when server doesn't know them, e.g. after restart.
+type ScriptManager struct {
+	mutex   sync.Mutex
+	scripts []*Script
+}
+
+func NewScriptManager() *ScriptManager {
+	return &ScriptManager{}
+}
+
+// Register adds script to install, scripts are installed in order of registration
+func (manager *ScriptManager) Register(name string, version int, source string) (script *Script) {
+	script = &Script{Name: name, Version: version, Source: source}
+	manager.mutex.Lock()
+	manager.scripts = append(manager.scripts, script)
+	manager.mutex.Unlock()
+	return
+}
+
+// Install installs all registered scripts which are missing or outdated on server
+func (manager *ScriptManager) Install(space *Space) (err error) {
+	manager.mutex.Lock()
+	scripts := append([]*Script{}, manager.scripts...)
+	manager.mutex.Unlock()
+
+	for _, script := range scripts {
+		_, err = script.Install(space)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Install evaluates script source unless the same version is installed already
+func (script *Script) Install(space *Space) (installed bool, err error) {
+	installed, err = script.install(space, false)
+	return
+}
+
+func (script *Script) install(space *Space, force bool) (installed bool, err error) {
+	script.mutex.Lock()
+	defer script.mutex.Unlock()
+
+	version := strconv.Itoa(script.Version)
+	if !force {
+		var res [][][]byte
+		res, err = space.DoString(scriptVersionCode, String(script.Name))
+		if err != nil {
+			return
+		}
+		if len(res) == 1 && len(res[0]) == 1 && string(res[0][0]) == version {
+			return
+		}
+	}
+
+	_, err = space.DoString(script.Source)
+	if err != nil {
+		return
+	}
+	_, err = space.DoString(scriptMarkCode, String(script.Name), String(version))
+	installed = err == nil
+	return
+}
+
+func (script *Script) Procedure(name string) *Procedure {
+	return &Procedure{name, script}
+}
+
+// Call calls procedure, script is installed and call is retried once
+// if server doesn't know procedure
+func (proc *Procedure) Call(space *Space, args ...TupleField) (tuples [][][]byte, err error) {
+	tuples, err = space.Call(proc.Name, true, args...)
+	if ErrorCode(err) != CodeNoSuchProc {
+		return
+	}
+
+	_, err = proc.script.install(space, true)
+	if err != nil {
+		return
+	}
+	tuples, err = space.Call(proc.Name, true, args...)
+	return
+}
+
+// Builtins holds lua procedures used by the package. They are installed
+// on the first call, install them on connect to save the retry.
+var Builtins = NewScriptManager()
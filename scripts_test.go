@@ -0,0 +1,54 @@
+package tarantool
+
+import (
+	"testing"
+)
+
+func TestScriptManager(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	space := conn.Space(0)
+
+	scripts := NewScriptManager()
+	script := scripts.Register("test_greet", 1, `function test_greet(name) return 'Hello, ' .. name end`)
+	greet := script.Procedure("test_greet")
+
+	err := scripts.Install(space)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	installed, err := script.Install(space)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if installed {
+		t.Errorf("Script of the same version should not be reinstalled")
+	}
+
+	res, err := greet.Call(space, String("Mary"))
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if len(res) != 1 || string(res[0][0]) != "Hello, Mary" {
+		t.Errorf("Procedure should greet Mary, not %q", res)
+	}
+
+	// Procedure is reinstalled when server forgets it
+	space.DoString("test_greet = nil")
+	res, err = greet.Call(space, String("Linda"))
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if len(res) != 1 || string(res[0][0]) != "Hello, Linda" {
+		t.Errorf("Procedure should greet Linda, not %q", res)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	var err error = &Error{CodeTupleFound, "Duplicate key exists in unique index 0"}
+	if ErrorCode(err) != CodeTupleFound {
+		t.Errorf("Error code should be %d not %d", CodeTupleFound, ErrorCode(err))
+	}
+	if err.Error() != "Return code is not 0, but 14082; Error message: Duplicate key exists in unique index 0" {
+		t.Errorf("Unexpected error message %s", err.Error())
+	}
+}
@@ -0,0 +1,174 @@
+package tarantool
+
+import (
+	"net/http"
+	"time"
+)
+
+// SessionLayout tells which fields of tuple keep session id (STR),
+// data (STR) and expiration time (NUM64 unix time in milliseconds).
+// Other fields up to the last one are written empty.
+type SessionLayout struct {
+	IdField      int32
+	DataField    int32
+	ExpiresField int32
+}
+
+var DefaultSessionLayout = SessionLayout{0, 1, 2}
+
+// SessionStore keeps sessions in a space with primary index on id.
+// It doesn't depend on any web framework, http helpers use a cookie
+// to keep session id.
+type SessionStore struct {
+	space  *Space
+	layout SessionLayout
+	ttl    time.Duration
+
+	CookieName string
+	CookiePath string
+	Secure     bool
+}
+
+// SessionStore returns a store of sessions expiring after ttl
+// since they were saved or touched
+func (conn *Connection) SessionStore(spaceNo int32, layout SessionLayout, ttl time.Duration) *SessionStore {
+	return &SessionStore{
+		space:      conn.Space(spaceNo),
+		layout:     layout,
+		ttl:        ttl,
+		CookieName: "session",
+		CookiePath: "/",
+	}
+}
+
+// NewId returns a random session id
+func (store *SessionStore) NewId() string {
+	return newToken()
+}
+
+// Load returns ok false for missing and expired sessions
+func (store *SessionStore) Load(id string) (data []byte, ok bool, err error) {
+	tuples, err := store.space.Select(0, 0, 1, []TupleField{String(id)})
+	if err != nil || len(tuples) == 0 {
+		return
+	}
+	tuple := tuples[0]
+	if int(store.layout.DataField) >= len(tuple) || int(store.layout.ExpiresField) >= len(tuple) {
+		return
+	}
+
+	var expires Int64
+	err = expires.Unpack(tuple[store.layout.ExpiresField])
+	if err != nil || int64(expires) <= unixMillis(time.Now()) {
+		return
+	}
+	return tuple[store.layout.DataField], true, nil
+}
+
+func (store *SessionStore) Save(id string, data []byte) (err error) {
+	size := store.layout.IdField
+	for _, fieldNo := range []int32{store.layout.DataField, store.layout.ExpiresField} {
+		if fieldNo > size {
+			size = fieldNo
+		}
+	}
+	tuple := make([]TupleField, size+1)
+	for i := range tuple {
+		tuple[i] = String("")
+	}
+	tuple[store.layout.IdField] = String(id)
+	tuple[store.layout.DataField] = String(data)
+	tuple[store.layout.ExpiresField] = store.expires()
+
+	_, err = store.space.Insert(tuple, false)
+	return
+}
+
+func (store *SessionStore) Delete(id string) (err error) {
+	_, err = store.space.Delete([]TupleField{String(id)}, false)
+	return
+}
+
+// Touch extends session expiration, ErrNotFound is returned
+// for missing and expired sessions. Expiration is changed only if
+// it is the same as loaded, so expired session can't be revived.
+func (store *SessionStore) Touch(id string) (err error) {
+	key := []TupleField{String(id)}
+	for {
+		var tuples [][][]byte
+		tuples, err = store.space.Select(0, 0, 1, key)
+		if err != nil {
+			return
+		}
+		if len(tuples) == 0 || int(store.layout.ExpiresField) >= len(tuples[0]) {
+			return ErrNotFound
+		}
+
+		var expires Int64
+		err = expires.Unpack(tuples[0][store.layout.ExpiresField])
+		if err != nil {
+			return
+		}
+		if int64(expires) <= unixMillis(time.Now()) {
+			return ErrNotFound
+		}
+
+		_, err = store.space.CompareAndSet(key, store.layout.ExpiresField, expires, UpdOp{store.layout.ExpiresField, OpEq, store.expires()})
+		if err != ErrConflict {
+			return
+		}
+		// saved or touched meanwhile
+	}
+}
+
+// LoadRequest loads session by id from request cookie,
+// empty id means there is no cookie
+func (store *SessionStore) LoadRequest(r *http.Request) (id string, data []byte, ok bool, err error) {
+	cookie, err := r.Cookie(store.CookieName)
+	if err == http.ErrNoCookie {
+		return "", nil, false, nil
+	}
+	if err != nil {
+		return
+	}
+	id = cookie.Value
+	data, ok, err = store.Load(id)
+	return
+}
+
+// SaveResponse saves session and sets its cookie,
+// it should be called before response body is written
+func (store *SessionStore) SaveResponse(w http.ResponseWriter, id string, data []byte) (err error) {
+	err = store.Save(id, data)
+	if err != nil {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     store.CookieName,
+		Value:    id,
+		Path:     store.CookiePath,
+		Expires:  time.Now().Add(store.ttl),
+		Secure:   store.Secure,
+		HttpOnly: true,
+	})
+	return
+}
+
+// DeleteResponse deletes session and its cookie
+func (store *SessionStore) DeleteResponse(w http.ResponseWriter, id string) (err error) {
+	err = store.Delete(id)
+	if err != nil {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   store.CookieName,
+		Value:  "",
+		Path:   store.CookiePath,
+		MaxAge: -1,
+	})
+	return
+}
+
+func (store *SessionStore) expires() TupleField {
+	return Int64(unixMillis(time.Now().Add(store.ttl)))
+}
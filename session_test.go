@@ -0,0 +1,63 @@
+package tarantool
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSessionStore(t *testing.T) {
+	conn, _ := Connect("localhost:33013")
+	// Cache space has the same layout
+	store := conn.SessionStore(14, DefaultSessionLayout, 100*time.Millisecond)
+	defer conn.Space(14).Truncate()
+
+	id := store.NewId()
+	recorder := httptest.NewRecorder()
+	err := store.SaveResponse(recorder, id, []byte("user=1"))
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+
+	request := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range recorder.Result().Cookies() {
+		request.AddCookie(cookie)
+	}
+	loadedId, data, ok, err := store.LoadRequest(request)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	if !ok || loadedId != id || string(data) != "user=1" {
+		t.Errorf("Session %s should be loaded from cookie, got %s %q", id, loadedId, data)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	err = store.Touch(id)
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, ok, _ = store.Load(id); !ok {
+		t.Errorf("Touched session should not expire")
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, ok, _ = store.Load(id); ok {
+		t.Errorf("Session should expire")
+	}
+	if err = store.Touch(id); err != ErrNotFound {
+		t.Errorf("ErrNotFound expected for expired session, not %v", err)
+	}
+	if _, ok, _ = store.Load(id); ok {
+		t.Errorf("Expired session should not be revived by Touch")
+	}
+
+	store.Delete(id)
+	if err = store.Touch(id); err != ErrNotFound {
+		t.Errorf("ErrNotFound expected for deleted session, not %v", err)
+	}
+
+	_, _, ok, err = store.LoadRequest(httptest.NewRequest("GET", "/", nil))
+	if ok || err != nil {
+		t.Errorf("Request without cookie should have no session, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package tarantool
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SlowEntry is a request reported by SlowLog. Key is a preview of
+// the first key, tuple or call arguments.
+type SlowEntry struct {
+	Op       string
+	SpaceNo  int32
+	IndexNo  int32
+	ProcName string
+	Key      string
+	Duration time.Duration
+	Err      error
+	// Sampled entries are normal requests reported for comparison
+	Sampled bool
+}
+
+type SlowLogOptions struct {
+	// Requests longer than Threshold are reported, 100ms by default
+	Threshold time.Duration
+	// SampleRate is a fraction of other requests reported as Sampled
+	SampleRate float64
+	// Redact formats fields in key preview, use RedactField to hide
+	// them. Numbers, printable strings and hex are shown by default.
+	Redact func(field []byte) string
+	// Key preview is cut to MaxPreview bytes, 100 by default
+	MaxPreview int
+	// Handler gets reported entries, they are logged by default
+	Handler func(entry SlowEntry)
+}
+
+// SlowLog returns interceptor reporting slow and sampled requests
+func SlowLog(opts SlowLogOptions) Interceptor {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 100 * time.Millisecond
+	}
+	if opts.Redact == nil {
+		opts.Redact = previewField
+	}
+	if opts.MaxPreview <= 0 {
+		opts.MaxPreview = 100
+	}
+	if opts.Handler == nil {
+		opts.Handler = func(entry SlowEntry) {
+			log.Print(entry.String())
+		}
+	}
+
+	return func(req *Request, next Handler) (tuples [][][]byte, err error) {
+		start := time.Now()
+		tuples, err = next(req)
+		duration := time.Since(start)
+
+		slow := duration >= opts.Threshold
+		if !slow && (opts.SampleRate <= 0 || rand.Float64() >= opts.SampleRate) {
+			return
+		}
+		opts.Handler(SlowEntry{
+			Op:       req.OpName(),
+			SpaceNo:  req.SpaceNo,
+			IndexNo:  req.IndexNo(),
+			ProcName: req.ProcName(),
+			Key:      keyPreview(req, opts.Redact, opts.MaxPreview),
+			Duration: duration,
+			Err:      err,
+			Sampled:  !slow,
+		})
+		return
+	}
+}
+
+func (entry SlowEntry) String() string {
+	kind := "slow"
+	if entry.Sampled {
+		kind = "sampled"
+	}
+	target := fmt.Sprintf("space %d index %d", entry.SpaceNo, entry.IndexNo)
+	if entry.ProcName != "" {
+		target = entry.ProcName
+	}
+	result := ""
+	if entry.Err != nil {
+		result = ": " + entry.Err.Error()
+	}
+	return fmt.Sprintf("%s %s %s key %s took %s%s", kind, entry.Op, target, entry.Key, entry.Duration, result)
+}
+
+// RedactField hides field content leaving its size
+func RedactField(field []byte) string {
+	return fmt.Sprintf("<%d bytes>", len(field))
+}
+
+// keyPreview formats the first key with redact, cut to max bytes unless max
+// is 0, and counts the rest
+func keyPreview(req *Request, redact func([]byte) string, max int) string {
+	keys, err := req.Keys()
+	if err != nil || len(keys) == 0 {
+		return "()"
+	}
+	fields := make([]string, len(keys[0]))
+	for i, field := range keys[0] {
+		fields[i] = redact(field)
+	}
+	preview := "(" + strings.Join(fields, ", ") + ")"
+	if max > 0 && len(preview) > max {
+		preview = preview[:max] + "..."
+	}
+	if len(keys) > 1 {
+		preview += fmt.Sprintf(" and %d more", len(keys)-1)
+	}
+	return preview
+}
+
+// previewField shows printable fields as strings, other 4 and 8 byte
+// fields as numbers
+func previewField(field []byte) string {
+	if len(field) > 0 && utf8.Valid(field) && strings.IndexFunc(string(field), func(r rune) bool { return r < ' ' }) < 0 {
+		return strconv.Quote(string(field))
+	}
+	switch len(field) {
+	case 4:
+		return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(field)), 10)
+	case 8:
+		return strconv.FormatUint(binary.LittleEndian.Uint64(field), 10)
+	}
+	return "0x" + hex.EncodeToString(field)
+}
@@ -0,0 +1,55 @@
+package tarantool
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowLog(t *testing.T) {
+	entries := []SlowEntry{}
+	conn := &Connection{}
+	conn.Use(SlowLog(SlowLogOptions{
+		Threshold: 20 * time.Millisecond,
+		Handler:   func(entry SlowEntry) { entries = append(entries, entry) },
+	}), func(req *Request, next Handler) ([][][]byte, error) {
+		if req.ProcName() == "slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return nil, nil
+	})
+
+	conn.Space(1).Select(0, 0, 1, []TupleField{String("fast")})
+	conn.Space(1).Call("slow", true, String("user"), Int32(42))
+
+	if len(entries) != 1 {
+		t.Fatalf("Only slow request should be reported, got %v", entries)
+	}
+	entry := entries[0]
+	if entry.Sampled || entry.ProcName != "slow" || entry.Key != `("user", 42)` {
+		t.Errorf("Wrong entry %+v", entry)
+	}
+	if !strings.HasPrefix(entry.String(), `slow call slow key ("user", 42) took `) {
+		t.Errorf("Wrong entry format %s", entry.String())
+	}
+}
+
+func TestSlowLogSampling(t *testing.T) {
+	entries := []SlowEntry{}
+	conn := &Connection{}
+	conn.Use(SlowLog(SlowLogOptions{
+		SampleRate: 1,
+		Redact:     RedactField,
+		Handler:    func(entry SlowEntry) { entries = append(entries, entry) },
+	}), func(req *Request, next Handler) ([][][]byte, error) {
+		return nil, nil
+	})
+
+	conn.Space(1).Select(0, 0, 1, []TupleField{String("secret")}, []TupleField{String("other")})
+	if len(entries) != 1 || !entries[0].Sampled {
+		t.Fatalf("Request should be sampled, got %v", entries)
+	}
+	if entries[0].Key != "(<6 bytes>) and 1 more" {
+		t.Errorf("Key should be redacted, got %s", entries[0].Key)
+	}
+}
@@ -8,10 +8,12 @@
 package tarantool
 
 import (
+	"context"
 	"fmt"
 	"github.com/fl00r/go-iproto"
 	"bytes"
 	"encoding/binary"
+	"sync"
 	// "reflect"
 )
 
@@ -39,15 +41,26 @@ const (
 	OpSplice  = int8(5) // not implemented
 	OpDelete  = int8(6)
 	OpPrepend = int8(7)
+
+	// Return codes
+	CodeTupleNotFound = int32(0x3102)
+	CodeNoSuchProc    = int32(0x3202)
+	CodeProcLua       = int32(0x3302)
+	CodeTupleFound    = int32(0x3702)
 )
 
 type Space struct {
-	spaceNo int32
-	conn    *iproto.IProto
+	spaceNo      int32
+	conn         *Connection
+	interceptors []Interceptor
+	ctx          context.Context
 }
 
 type Connection struct {
 	conn *iproto.IProto
+
+	mutex        sync.RWMutex
+	interceptors []Interceptor
 }
 
 type SelectRequestBody struct {
@@ -73,6 +86,14 @@ type UpdOp struct {
 	Field   TupleField
 }
 
+// Error is returned when tarantool responds with non zero return code
+type Error struct {
+	Code    int32
+	Message string
+}
+
+type Int64 int64
+
 type Int32 int32
 
 type Int8 int8
@@ -87,6 +108,17 @@ type TypeToReturn interface {
 	Unpack([][]byte) error
 }
 
+func (val Int64) Pack(buffer *bytes.Buffer) (err error) {
+	buf := make([]byte, 1)
+	binary.PutUvarint(buf, uint64(8))
+	_, err = buffer.Write(buf)
+	if err != nil {
+		return
+	}
+	err = binary.Write(buffer, binary.LittleEndian, val)
+	return
+}
+
 func (val Int32) Pack(buffer *bytes.Buffer) (err error) {
 	buf := make([]byte, 1)
 	binary.PutUvarint(buf, uint64(4))
@@ -121,6 +153,11 @@ func (val String) Pack(buffer *bytes.Buffer) (err error) {
 	return
 }
 
+func (val *Int64) Unpack(packet []byte) (err error) {
+	err = binary.Read(bytes.NewBuffer(packet), binary.LittleEndian, val)
+	return 
+}
+
 func (val *Int32) Unpack(packet []byte) (err error) {
 	err = binary.Read(bytes.NewBuffer(packet), binary.LittleEndian, val)
 	return 
@@ -137,14 +174,39 @@ func (val *String) Unpack(packet []byte) (err error) {
 }
 
 
+func (err *Error) Error() string {
+	return fmt.Sprintf("Return code is not 0, but %d; Error message: %s", err.Code, err.Message)
+}
+
+// ProtocolError is returned when response can't be decoded
+type ProtocolError struct {
+	Err error
+}
+
+func (err *ProtocolError) Error() string {
+	return "Malformed response: " + err.Err.Error()
+}
+
+func (err *ProtocolError) Unwrap() error {
+	return err.Err
+}
+
+// ErrorCode returns tarantool return code of err or 0 for other errors
+func ErrorCode(err error) int32 {
+	if tntErr, ok := err.(*Error); ok {
+		return tntErr.Code
+	}
+	return 0
+}
+
 func Connect(addr string) (conn *Connection, err error) {
 	ipr, err := iproto.Connect(addr)
-	conn = &Connection{ ipr }
+	conn = &Connection{ conn: ipr }
 	return
 }
 
 func (conn *Connection) Space(spaceNo int32) (space *Space) {
-	space = &Space{ spaceNo, conn.conn }
+	space = &Space{ spaceNo: spaceNo, conn: conn }
 	return
 }
 
@@ -357,33 +419,52 @@ func (space *Space) Ping() (tuples [][][]byte, err error) {
 }
 
 func (space *Space) request(requestId int32, body *bytes.Buffer) (tuples [][][]byte, err error) {
-	var (
-		returnCode  int32
-		tuplesCount int32
-		tuplesSize  int32
-		cardinality int32
-		size        uint64
-		response    *iproto.Response
-	)
+	req := &Request{ Op: requestId, SpaceNo: space.spaceNo, Body: body.Bytes(), ctx: space.ctx }
+	tuples, err = space.chain()(req)
+	return
+}
 
-	response, err = space.conn.Request(requestId, body)
+// send is the last handler of interceptors chain
+func (conn *Connection) send(req *Request) (tuples [][][]byte, err error) {
+	err = req.Context().Err()
+	if err != nil {
+		return
+	}
+
+	response, err := conn.conn.Request(req.Op, bytes.NewBuffer(req.Body))
 	if err != nil {
 		return
 	}
 
 	// Ping has no Body
-	if requestId == PingOp {
+	if req.Op == PingOp {
 		tuples = [][][]byte{}
 		return
 	}
 
+	tuples, err = decodeResponse(response)
+	if err != nil && ErrorCode(err) == 0 {
+		err = &ProtocolError{err}
+	}
+	return
+}
+
+func decodeResponse(response *iproto.Response) (tuples [][][]byte, err error) {
+	var (
+		returnCode  int32
+		tuplesCount int32
+		tuplesSize  int32
+		cardinality int32
+		size        uint64
+	)
+
 	err = binary.Read(response.Body, binary.LittleEndian, &returnCode)
 	if err != nil {
 		return
 	}
 
 	if returnCode != 0 {
-		err = fmt.Errorf("Return code is not 0, but %d; Error message: %s", returnCode, response.Body.String())
+		err = &Error{returnCode, response.Body.String()}
 		return
 	}
 	err = binary.Read(response.Body, binary.LittleEndian, &tuplesCount)
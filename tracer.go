@@ -0,0 +1,99 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Span describes one request. Sent is number of select keys,
+// or one for other requests with a tuple, Received and Err are set
+// before EndSpan.
+type Span struct {
+	Op       string
+	SpaceNo  int32
+	IndexNo  int32
+	ProcName string
+	Sent     int
+	Received int
+	Err      error
+}
+
+// Tracer is called around every request. StartSpan may return
+// a context with span in it, the same context is passed to EndSpan
+// and to the rest of the chain.
+type Tracer interface {
+	StartSpan(ctx context.Context, span *Span) context.Context
+	EndSpan(ctx context.Context, span *Span)
+}
+
+// Trace returns interceptor reporting requests to tracer,
+// pass context to requests with Space.WithContext
+func Trace(tracer Tracer) Interceptor {
+	return func(req *Request, next Handler) (tuples [][][]byte, err error) {
+		keys, _ := req.Keys()
+		span := &Span{
+			Op:       req.OpName(),
+			SpaceNo:  req.SpaceNo,
+			IndexNo:  req.IndexNo(),
+			ProcName: req.ProcName(),
+			Sent:     len(keys),
+		}
+		ctx := tracer.StartSpan(req.Context(), span)
+		tuples, err = next(req.WithContext(ctx))
+		span.Received, span.Err = len(tuples), err
+		tracer.EndSpan(ctx, span)
+		return
+	}
+}
+
+// LogTracer writes finished spans to Logger, standard error by default.
+// It is for local debugging, spans have ids and are nested in parent
+// spans found in context.
+type LogTracer struct {
+	Logger *log.Logger
+
+	lastId int64
+}
+
+type logSpanKey struct{}
+
+type logSpan struct {
+	id, parent int64
+	start      time.Time
+}
+
+func (tracer *LogTracer) StartSpan(ctx context.Context, span *Span) context.Context {
+	started := &logSpan{id: atomic.AddInt64(&tracer.lastId, 1), start: time.Now()}
+	if parent, ok := ctx.Value(logSpanKey{}).(*logSpan); ok {
+		started.parent = parent.id
+	}
+	return context.WithValue(ctx, logSpanKey{}, started)
+}
+
+func (tracer *LogTracer) EndSpan(ctx context.Context, span *Span) {
+	started, ok := ctx.Value(logSpanKey{}).(*logSpan)
+	if !ok {
+		return
+	}
+	logger := tracer.Logger
+	if logger == nil {
+		logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+
+	target := ""
+	if span.ProcName != "" {
+		target = " " + span.ProcName
+	} else if span.Op != "ping" {
+		target = fmt.Sprintf(" space %d index %d", span.SpaceNo, span.IndexNo)
+	}
+	result := "ok"
+	if span.Err != nil {
+		result = span.Err.Error()
+	}
+	logger.Printf("span %d parent %d: %s%s sent %d received %d in %s: %s",
+		started.id, started.parent, span.Op, target, span.Sent, span.Received, time.Since(started.start), result)
+}
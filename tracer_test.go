@@ -0,0 +1,59 @@
+package tarantool
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+type recordingTracer struct {
+	spans []Span
+}
+
+func (tracer *recordingTracer) StartSpan(ctx context.Context, span *Span) context.Context {
+	return ctx
+}
+
+func (tracer *recordingTracer) EndSpan(ctx context.Context, span *Span) {
+	tracer.spans = append(tracer.spans, *span)
+}
+
+func TestTrace(t *testing.T) {
+	tracer := &recordingTracer{}
+	buffer := new(bytes.Buffer)
+	conn := &Connection{}
+	conn.Use(Trace(tracer), Trace(&LogTracer{Logger: log.New(buffer, "", 0)}), func(req *Request, next Handler) ([][][]byte, error) {
+		return [][][]byte{{}, {}}, nil
+	})
+
+	conn.Space(1).Select(2, 0, 10, []TupleField{String("a")}, []TupleField{String("b")})
+	conn.Space(0).Call("box.time", true)
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("2 spans expected, not %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.Op != "select" || span.SpaceNo != 1 || span.IndexNo != 2 || span.Sent != 2 || span.Received != 2 {
+		t.Errorf("Wrong select span %+v", span)
+	}
+	if tracer.spans[1].ProcName != "box.time" {
+		t.Errorf("Call span should have proc name, got %+v", tracer.spans[1])
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "span 1 parent 0: select space 1 index 2 sent 2 received 2 in ") {
+		t.Errorf("Wrong spans logged:\n%s", buffer.String())
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	conn := &Connection{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := conn.Space(1).WithContext(ctx).Ping()
+	if err != context.Canceled {
+		t.Errorf("Request with canceled context should not be sent, got %v", err)
+	}
+}